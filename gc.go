@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"strings"
+
+	p "github.com/daytonaio/daytona-provider-digitalocean/pkg/provider"
+)
+
+// Collects the orphaned resources of a Daytona server, e.g.
+// daytona-provider-digitalocean gc --server-url https://daytona.example.com --live-targets "$(daytona target list -f json | jq -r 'map(.id) | join(",")')"
+func runGc(args []string) error {
+	flags := flag.NewFlagSet("gc", flag.ContinueOnError)
+	serverUrl := flags.String("server-url", "", "URL of the Daytona server whose resources are collected")
+	basePath := flags.String("base-path", "", "Base path of the provider, used to read its config.json")
+	liveTargets := flags.String("live-targets", "", "Comma separated IDs of the targets that exist in Daytona")
+	deleteOrphans := flags.Bool("delete", false, "Delete the orphaned resources instead of listing them")
+	includeUntaggedServer := flags.Bool("include-untagged-server", false, "Also collect daytona tagged resources without a server tag, only safe if no other server uses the account")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *serverUrl == "" {
+		return errors.New("--server-url is required")
+	}
+
+	// An empty list would collect every target of the server, so it must be given explicitly
+	liveTargetsSet := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "live-targets" {
+			liveTargetsSet = true
+		}
+	})
+	if !liveTargetsSet {
+		return errors.New("--live-targets is required")
+	}
+
+	liveTargetIds := []string{}
+	for _, targetId := range strings.Split(*liveTargets, ",") {
+		targetId = strings.TrimSpace(targetId)
+		if targetId != "" {
+			liveTargetIds = append(liveTargetIds, targetId)
+		}
+	}
+
+	provider := &p.DigitalOceanProvider{ServerUrl: serverUrl}
+	if *basePath != "" {
+		provider.BasePath = basePath
+	}
+
+	_, err = provider.CollectOrphanedResources(liveTargetIds, *includeUntaggedServer, !*deleteOrphans, os.Stdout)
+	return err
+}
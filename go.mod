@@ -13,6 +13,7 @@ require (
 	github.com/hashicorp/go-plugin v1.6.0
 	github.com/sirupsen/logrus v1.9.3
 	golang.org/x/oauth2 v0.22.0
+	gopkg.in/yaml.v3 v3.0.1
 	tailscale.com v1.72.1
 )
 
@@ -178,7 +179,6 @@ require (
 	golang.zx2c4.com/wireguard/windows v0.5.3 // indirect
 	gopkg.in/ini.v1 v1.67.0 // indirect
 	gopkg.in/warnings.v0 v0.1.2 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 	gvisor.dev/gvisor v0.0.0-20240722211153-64c016c92987 // indirect
 )
 
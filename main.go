@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/daytonaio/daytona/pkg/provider"
@@ -12,6 +13,15 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "gc" {
+		err := runGc(os.Args[2:])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	logger := hclog.New(&hclog.LoggerOptions{
 		Level:      hclog.Trace,
 		Output:     os.Stderr,
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/daytonaio/daytona-provider-digitalocean/pkg/types"
+)
+
+// Name of the file under the provider's base path holding provider-wide settings
+const configFileName = "config.json"
+
+// DigitalOcean's default limit on the total size of an account's volumes per region
+const defaultVolumeStorageLimitGigaBytes = 16 * 1024
+
+type providerConfig struct {
+	// Used for targets that do not set the Max Monthly Cost option
+	MaxMonthlyCost *float64 `json:"maxMonthlyCost,omitempty"`
+	// Total size of volumes per region in GB, for accounts with a raised limit
+	VolumeStorageLimit *int `json:"volumeStorageLimit,omitempty"`
+	// Target configs can be edited by every Daytona user, so references that read files or run commands
+	// on the server are only resolved in their Auth Token if enabled
+	AllowTokenReferences bool `json:"allowTokenReferences,omitempty"`
+	// Falls back to the token of the active doctl context if neither the Auth Token nor the environment variable is set
+	UseDoctlConfig bool `json:"useDoctlConfig,omitempty"`
+}
+
+func (p *DigitalOceanProvider) readConfig() (*providerConfig, error) {
+	config := &providerConfig{}
+	if p.BasePath == nil {
+		return config, nil
+	}
+
+	content, err := os.ReadFile(filepath.Join(*p.BasePath, configFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(content, config)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", configFileName, err)
+	}
+
+	return config, nil
+}
+
+// Returns an error if the estimated monthly cost exceeds the target's limit or the provider-wide default
+func (p *DigitalOceanProvider) checkBudget(targetOptions *types.TargetOptions, costEstimate *types.CostEstimate) error {
+	maxMonthlyCost := targetOptions.MaxMonthlyCost
+	if maxMonthlyCost == nil {
+		config, err := p.readConfig()
+		if err != nil {
+			return err
+		}
+		maxMonthlyCost = config.MaxMonthlyCost
+	}
+
+	if maxMonthlyCost == nil || *maxMonthlyCost <= 0 {
+		return nil
+	}
+
+	if costEstimate.TotalMonthly > *maxMonthlyCost {
+		return fmt.Errorf("estimated cost of $%.2f/month exceeds the maximum monthly cost of $%.2f", costEstimate.TotalMonthly, *maxMonthlyCost)
+	}
+
+	return nil
+}
+
+func (p *DigitalOceanProvider) getVolumeStorageLimit() (int, error) {
+	config, err := p.readConfig()
+	if err != nil {
+		return 0, err
+	}
+
+	if config.VolumeStorageLimit != nil {
+		return *config.VolumeStorageLimit, nil
+	}
+
+	return defaultVolumeStorageLimitGigaBytes, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"slices"
 	"time"
 
 	log_writers "github.com/daytonaio/daytona-provider-digitalocean/internal/log"
@@ -37,12 +38,47 @@ func (p *DigitalOceanProvider) CreateTarget(targetReq *provider.TargetRequest) (
 		return new(provider_util.Empty), err
 	}
 
-	_, err = p.createDroplet(client, targetReq.Target, targetOptions, logWriter)
+	sizes, err := util.ListSizes(client)
+	if err != nil {
+		logWriter.Write([]byte("Failed to list sizes: " + err.Error() + "\n"))
+		return new(provider_util.Empty), err
+	}
+
+	err = util.ValidateTargetOptions(client, targetOptions, sizes, util.DropletCreated)
+	if err != nil {
+		logWriter.Write([]byte("Invalid target config options: " + err.Error() + "\n"))
+		return new(provider_util.Empty), err
+	}
+
+	costEstimate, err := util.EstimateCost(sizes, targetOptions)
+	if err != nil {
+		logWriter.Write([]byte("Failed to estimate cost: " + err.Error() + "\n"))
+		return new(provider_util.Empty), err
+	}
+	logWriter.Write([]byte(costEstimate.String() + "\n"))
+
+	err = p.checkBudget(targetOptions, costEstimate)
+	if err != nil {
+		logWriter.Write([]byte(err.Error() + "\n"))
+		return new(provider_util.Empty), err
+	}
+
+	droplet, err := p.createDroplet(client, targetReq.Target, targetOptions, sizes, logWriter)
 	if err != nil {
 		logWriter.Write([]byte("Failed to create droplet: " + err.Error() + "\n"))
 		return new(provider_util.Empty), err
 	}
 
+	err = p.assignReservedIp(client, targetReq.Target, targetOptions, droplet, logWriter)
+	if err != nil {
+		logWriter.Write([]byte("Failed to assign reserved IP: " + err.Error() + "\n"))
+		return new(provider_util.Empty), err
+	}
+
+	if targetOptions.MaxLifetime != nil && *targetOptions.MaxLifetime != "" {
+		p.startReaper(targetOptions)
+	}
+
 	dockerClient, err := p.getDockerClient(targetReq.Target.Id)
 	if err != nil {
 		logWriter.Write([]byte("Failed to get docker client: " + err.Error() + "\n"))
@@ -92,11 +128,27 @@ func (p *DigitalOceanProvider) CreateWorkspace(workspaceReq *provider.WorkspaceR
 	})
 }
 
-func (p *DigitalOceanProvider) createDroplet(client *godo.Client, tg *models.Target, targetOptions *types.TargetOptions, logWriter io.Writer) (*godo.Droplet, error) {
+// Returns the existing droplet or creates it with one of the sizes, which must include the target's size
+func (p *DigitalOceanProvider) createDroplet(client *godo.Client, tg *models.Target, targetOptions *types.TargetOptions, sizes []godo.Size, logWriter io.Writer) (*godo.Droplet, error) {
 	dropletName := util.GetDropletName(tg)
 
+	inboundRules := []godo.InboundRule{}
+	if targetOptions.FirewallInboundRules != nil {
+		var err error
+		inboundRules, err = util.ParseFirewallInboundRules(*targetOptions.FirewallInboundRules)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	existingDroplet, err := util.GetDroplet(client, dropletName)
 	if err == nil && existingDroplet != nil {
+		// Applies inbound rules changed since the droplet was created
+		_, err = util.CreateOrUpdateFirewall(client, dropletName, inboundRules)
+		if err != nil {
+			return nil, err
+		}
+
 		return existingDroplet, nil
 	}
 
@@ -104,21 +156,97 @@ func (p *DigitalOceanProvider) createDroplet(client *godo.Client, tg *models.Tar
 
 	tg.EnvVars["DAYTONA_AGENT_LOG_FILE_PATH"] = "/home/daytona/.daytona-agent.log"
 
+	tags, err := p.getTargetTags(tg, targetOptions)
+	if err != nil {
+		return nil, err
+	}
+
 	volume, err := util.GetVolumeByName(client, dropletName)
 	if err != nil {
 		return nil, err
-	} else if volume == nil {
-		volume, _, err = client.Storage.CreateVolume(context.Background(), &godo.VolumeCreateRequest{
-			Name:            dropletName,
-			Region:          targetOptions.Region,
-			SizeGigaBytes:   int64(targetOptions.DiskSize),
-			FilesystemType:  "ext4",
-			FilesystemLabel: "Daytona Data",
-			Tags:            []string{"daytona"},
-		})
+	}
+
+	expiresAt, expires, err := getTargetExpiry(client, dropletName, volume, targetOptions)
+	if err != nil {
+		return nil, err
+	} else if expires {
+		tags = append(tags, util.GetExpiryTag(expiresAt))
+	}
+
+	newVolumeSize := 0
+	if volume == nil {
+		newVolumeSize = targetOptions.DiskSize
+	}
+
+	volumeStorageLimit, err := p.getVolumeStorageLimit()
+	if err != nil {
+		return nil, err
+	}
+
+	// Fail before creating anything that would be orphaned when the droplet creation is rejected
+	err = util.CheckAccountLimits(client, targetOptions.Region, newVolumeSize, volumeStorageLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	sshKeys := []godo.DropletCreateSSHKey{}
+	if targetOptions.SshKeys != nil {
+		sshKeys, err = util.GetSshKeys(client, *targetOptions.SshKeys)
 		if err != nil {
 			return nil, err
 		}
+
+		// The keys are meant for break-glass access when the agent is down, which the firewall would block
+		if len(sshKeys) > 0 && !util.AllowsPort(inboundRules, "tcp", 22) {
+			logWriter.Write([]byte("Warning: SSH keys are set but no firewall inbound rule allows port 22, add e.g. tcp:22:<cidr> to Firewall Inbound Rules to allow SSH.\n"))
+		}
+	}
+
+	vpcUuid := ""
+	if targetOptions.VpcUuid != nil && *targetOptions.VpcUuid != "" {
+		vpc, err := util.GetVpc(client, *targetOptions.VpcUuid, targetOptions.Region)
+		if err != nil {
+			return nil, err
+		}
+		vpcUuid = vpc.ID
+	} else if targetOptions.CreateVpc {
+		vpc, err := util.GetOrCreateDaytonaVpc(client, targetOptions.Region)
+		if err != nil {
+			return nil, err
+		}
+		vpcUuid = vpc.ID
+	}
+
+	image, err := p.getDropletImage(client, targetOptions, sizes, logWriter)
+	if err != nil {
+		return nil, err
+	}
+
+	var project *godo.Project
+	if targetOptions.Project != nil && *targetOptions.Project != "" {
+		project, err = util.GetProject(client, *targetOptions.Project)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	volumeCreated := volume == nil
+	if volume == nil {
+		volume, err = p.createVolume(client, dropletName, targetOptions, tags, logWriter)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// Volumes created by older versions of the provider are missing tags
+		err = util.TagResources(client, tags, godo.Resource{ID: volume.ID, Type: godo.VolumeResourceType})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dockerHosts := `"unix:///var/run/docker.sock"`
+	if targetOptions.ExposeDockerApi {
+		dockerHosts += `, "tcp://0.0.0.0:2375"`
 	}
 
 	// retrieve user data
@@ -128,21 +256,20 @@ umount /mnt/` + dropletName + `
 
 # Mount volume to home
 mkdir -p /home/daytona
-mount -o discard,defaults,noatime /dev/disk/by-id/scsi-0DO_Volume_` + dropletName + ` /home/daytona
+mount -o discard,defaults,noatime ` + util.GetVolumeDevicePath(dropletName) + ` /home/daytona
 
-echo '/dev/disk/by-id/scsi-0DO_Volume_` + dropletName + ` /home/daytona ext4 discard,defaults,noatime 0 0' | sudo tee -a /etc/fstab
+# Grow the filesystem if the volume was resized while no droplet was attached
+resize2fs ` + util.GetVolumeDevicePath(dropletName) + `
 
-# Check if docker is installed
-if ! command -v docker &> /dev/null; then
-  curl -fsSL https://get.docker.com | bash
-fi
+echo '` + util.GetVolumeDevicePath(dropletName) + ` /home/daytona ext4 discard,defaults,noatime 0 0' | sudo tee -a /etc/fstab
 
+` + installDockerScript + `
 # Move docker data dir
 service docker stop
 cat > /etc/docker/daemon.json << EOF
 {
   "data-root": "/home/daytona/.docker-daemon",
-	"hosts": ["unix:///var/run/docker.sock", "tcp://0.0.0.0:2375"],
+	"hosts": [` + dockerHosts + `],
   "live-restore": true
 }
 EOF
@@ -177,7 +304,10 @@ chown daytona:daytona /home/daytona
 		userData += fmt.Sprintf("export %s=%s\n", k, v)
 	}
 
-	userData += fmt.Sprintf(`curl -sfL -H "Authorization: Bearer %s" %s | bash`, tg.ApiKey, *p.DaytonaDownloadUrl)
+	// Baked images already contain the agent for the current Daytona version
+	userData += fmt.Sprintf(`if [ ! -x /usr/local/bin/daytona ] || [ "$(cat %s 2>/dev/null)" != "%s" ]; then
+  curl -sfL -H "Authorization: Bearer %s" %s | bash
+fi`, imageVersionFile, *p.DaytonaVersion, tg.ApiKey, *p.DaytonaDownloadUrl)
 	userData += `
 	echo '[Unit]
 Description=Daytona Agent Service
@@ -201,23 +331,59 @@ systemctl enable daytona-agent.service
 systemctl start daytona-agent.service
 `
 
+	if targetOptions.IdleTimeout > 0 {
+		userData += getIdleWatcherUserData(targetOptions.IdleTimeout)
+	}
+
+	if targetOptions.ReservedIp != nil && *targetOptions.ReservedIp != "" {
+		userData += getReservedIpRouteUserData()
+	}
+
+	_, err = util.CreateOrUpdateFirewall(client, dropletName, inboundRules)
+	if err != nil {
+		return nil, err
+	}
+
 	instance := &godo.DropletCreateRequest{
-		Name:   dropletName,
-		Region: targetOptions.Region,
-		Size:   targetOptions.Size,
-		Image: godo.DropletCreateImage{
-			Slug: targetOptions.Image,
-		},
-		UserData: userData,
-		Tags:     []string{"daytona"},
-		Volumes:  []godo.DropletCreateVolume{{ID: volume.ID}},
+		Name:              dropletName,
+		Region:            targetOptions.Region,
+		Size:              targetOptions.Size,
+		Image:             image,
+		UserData:          userData,
+		SSHKeys:           sshKeys,
+		Tags:              append(tags, dropletName),
+		Volumes:           []godo.DropletCreateVolume{{ID: volume.ID}},
+		VPCUUID:           vpcUuid,
+		PrivateNetworking: targetOptions.PrivateNetworking,
+		Monitoring:        targetOptions.Monitoring,
+		Backups:           targetOptions.Backups,
+		IPv6:              targetOptions.IPv6,
 	}
 
 	droplet, _, err := client.Droplets.Create(context.Background(), instance)
 	if err != nil {
+		deleteErr := util.DeleteFirewall(client, dropletName)
+		if deleteErr != nil {
+			logWriter.Write([]byte("Failed to delete firewall: " + deleteErr.Error() + "\n"))
+		}
+
+		if volumeCreated {
+			deleteErr := util.DeleteVolume(client, dropletName)
+			if deleteErr != nil {
+				logWriter.Write([]byte("Failed to delete volume: " + deleteErr.Error() + "\n"))
+			}
+		}
 		return nil, fmt.Errorf("error creating droplet: %v", err)
 	}
 
+	// The snapshot is only deleted once the restored volume is attached to a droplet
+	if volumeCreated {
+		err = util.DeleteVolumeSnapshot(client, dropletName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Poll the droplet's status until it becomes active
 	for {
 		droplet, _, err = client.Droplets.Get(context.Background(), droplet.ID)
@@ -232,6 +398,13 @@ systemctl start daytona-agent.service
 		time.Sleep(time.Second * 2)
 	}
 
+	if project != nil {
+		err = util.AssignToProject(client, project, droplet, volume)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	logWriter.Write([]byte("Droplet created.\n"))
 
 	initializingDropletSpinner := log_writers.ShowSpinner(logWriter, "Initializing droplet", "Droplet initialized")
@@ -247,3 +420,45 @@ systemctl start daytona-agent.service
 
 	return droplet, nil
 }
+
+// Creates the target's volume or restores it from the snapshot taken when the target was stopped
+// A restored volume can be safely deleted as its snapshot is kept
+func (p *DigitalOceanProvider) createVolume(client *godo.Client, name string, targetOptions *types.TargetOptions, tags []string, logWriter io.Writer) (*godo.Volume, error) {
+	snapshot, err := util.GetVolumeSnapshotByName(client, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if snapshot == nil {
+		volume, _, err := client.Storage.CreateVolume(context.Background(), &godo.VolumeCreateRequest{
+			Name:            name,
+			Region:          targetOptions.Region,
+			SizeGigaBytes:   int64(targetOptions.DiskSize),
+			FilesystemType:  "ext4",
+			FilesystemLabel: "Daytona Data",
+			Tags:            tags,
+		})
+		return volume, err
+	}
+
+	logWriter.Write([]byte("Restoring volume from snapshot...\n"))
+
+	// Keeps the reserved IP allocated for the target
+	restoredTags := tags
+	if ip := util.GetReservedIpFromTags(snapshot.Tags); ip != "" {
+		restoredTags = append(slices.Clone(tags), util.GetReservedIpTag(ip))
+	}
+
+	volume, _, err := client.Storage.CreateVolume(context.Background(), &godo.VolumeCreateRequest{
+		Name:          name,
+		Region:        targetOptions.Region,
+		SizeGigaBytes: max(int64(targetOptions.DiskSize), int64(snapshot.MinDiskSize)),
+		SnapshotID:    snapshot.ID,
+		Tags:          restoredTags,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error restoring volume from snapshot: %v", err)
+	}
+
+	return volume, nil
+}
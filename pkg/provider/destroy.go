@@ -24,12 +24,24 @@ func (p *DigitalOceanProvider) DestroyTarget(targetReq *provider.TargetRequest)
 		return new(provider_util.Empty), err
 	}
 
+	allocatedIp, err := getAllocatedReservedIp(client, targetReq.Target)
+	if err != nil {
+		logWriter.Write([]byte("Failed to get reserved IP: " + err.Error() + "\n"))
+		return new(provider_util.Empty), err
+	}
+
 	err = util.DeleteDroplet(client, targetReq.Target, true)
 	if err != nil {
 		logWriter.Write([]byte("Failed to delete droplet: " + err.Error() + "\n"))
 		return new(provider_util.Empty), err
 	}
 
+	err = releaseReservedIp(client, allocatedIp)
+	if err != nil {
+		logWriter.Write([]byte("Failed to release reserved IP: " + err.Error() + "\n"))
+		return new(provider_util.Empty), err
+	}
+
 	return new(provider_util.Empty), nil
 }
 
@@ -0,0 +1,265 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	logwriters "github.com/daytonaio/daytona-provider-digitalocean/internal/log"
+	"github.com/daytonaio/daytona-provider-digitalocean/pkg/provider/util"
+	"github.com/daytonaio/daytona-provider-digitalocean/pkg/types"
+	"github.com/digitalocean/godo"
+)
+
+const reaperInterval = 10 * time.Minute
+
+// Name of the file under the provider's base path holding the token references of the accounts with expiring targets
+// by the hash of the token, so their reapers are started again after a provider restart. Raw tokens are never persisted.
+const reapersFileName = "reapers.json"
+
+type reaper struct {
+	// Set when a target with a max lifetime was created or started since the reaper last listed the resources
+	pending bool
+}
+
+// Returns the time the target expires at, false if it has no max lifetime.
+// The expiry is set when the target's droplet is first created and kept in the tags of its volume and
+// volume snapshot across droplet recreations.
+func getTargetExpiry(client *godo.Client, name string, volume *godo.Volume, targetOptions *types.TargetOptions) (time.Time, bool, error) {
+	if targetOptions.MaxLifetime == nil || *targetOptions.MaxLifetime == "" {
+		return time.Time{}, false, nil
+	}
+
+	if volume != nil {
+		expiresAt, expires := util.GetExpiry(volume.Tags)
+		if expires {
+			return expiresAt, true, nil
+		}
+	} else {
+		snapshot, err := util.GetVolumeSnapshotByName(client, name)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+
+		if snapshot != nil {
+			expiresAt, expires := util.GetExpiry(snapshot.Tags)
+			if expires {
+				return expiresAt, true, nil
+			}
+		}
+	}
+
+	maxLifetime, err := time.ParseDuration(*targetOptions.MaxLifetime)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid max lifetime %s: %v", *targetOptions.MaxLifetime, err)
+	}
+
+	return time.Now().Add(maxLifetime), true, nil
+}
+
+// Periodically reaps expired targets of the account the target options authenticate with.
+// Only one reaper runs per account and it stops once none of the account's targets expire.
+func (p *DigitalOceanProvider) startReaper(targetOptions *types.TargetOptions) {
+	token := ""
+	if targetOptions.AuthToken != nil {
+		token = *targetOptions.AuthToken
+	}
+	hash := sha256.Sum256([]byte(token))
+	account := hex.EncodeToString(hash[:])
+
+	p.reapersMutex.Lock()
+	defer p.reapersMutex.Unlock()
+
+	if p.reapers == nil {
+		p.reapers = map[string]*reaper{}
+	}
+
+	if r, running := p.reapers[account]; running {
+		r.pending = true
+		return
+	}
+
+	r := &reaper{}
+	p.reapers[account] = r
+
+	logWriter := &logwriters.InfoLogWriter{}
+
+	// The default token is reaped with on every start anyway
+	if util.IsTokenReference(token) {
+		err := p.updatePersistedReapers(func(persisted map[string]string) { persisted[account] = token })
+		if err != nil {
+			logWriter.Write([]byte("Failed to persist reaper: " + err.Error() + "\n"))
+		}
+	}
+
+	reaperOptions := &types.TargetOptions{AuthToken: targetOptions.AuthToken}
+
+	go func() {
+		for {
+			p.reapersMutex.Lock()
+			r.pending = false
+			p.reapersMutex.Unlock()
+
+			expiring := false
+			client, err := p.getDoClient(reaperOptions)
+			if err == nil {
+				expiring, err = p.ReapExpiredTargets(client, logWriter)
+			}
+			if err != nil {
+				logWriter.Write([]byte("Failed to reap expired targets: " + err.Error() + "\n"))
+			}
+
+			p.reapersMutex.Lock()
+			if err == nil && !expiring && !r.pending {
+				delete(p.reapers, account)
+				err = p.updatePersistedReapers(func(persisted map[string]string) { delete(persisted, account) })
+				if err != nil {
+					logWriter.Write([]byte("Failed to remove persisted reaper: " + err.Error() + "\n"))
+				}
+				p.reapersMutex.Unlock()
+				return
+			}
+			p.reapersMutex.Unlock()
+
+			time.Sleep(reaperInterval)
+		}
+	}()
+}
+
+// Starts the reapers of the accounts that had expiring targets when the provider was last running
+func (p *DigitalOceanProvider) startPersistedReapers() error {
+	p.reapersMutex.Lock()
+	persisted, err := p.readPersistedReapers()
+	p.reapersMutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, tokenReference := range persisted {
+		targetOptions := &types.TargetOptions{AuthToken: &tokenReference}
+
+		// The reference is kept to be retried on the next start, e.g. once it resolves again
+		_, err := p.getDoClient(targetOptions)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error starting reaper for %s: %v", tokenReference, err))
+			continue
+		}
+
+		p.startReaper(targetOptions)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Must be called with the reapers mutex held
+func (p *DigitalOceanProvider) readPersistedReapers() (map[string]string, error) {
+	persisted := map[string]string{}
+	if p.BasePath == nil {
+		return persisted, nil
+	}
+
+	content, err := os.ReadFile(filepath.Join(*p.BasePath, reapersFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return persisted, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(content, &persisted)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", reapersFileName, err)
+	}
+
+	return persisted, nil
+}
+
+// Must be called with the reapers mutex held
+func (p *DigitalOceanProvider) updatePersistedReapers(update func(persisted map[string]string)) error {
+	if p.BasePath == nil {
+		return nil
+	}
+
+	persisted, err := p.readPersistedReapers()
+	if err != nil {
+		return err
+	}
+
+	update(persisted)
+
+	content, err := json.MarshalIndent(persisted, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Written to a temporary file first so a crash never leaves a truncated file behind
+	path := filepath.Join(*p.BasePath, reapersFileName)
+	err = os.WriteFile(path+".tmp", content, 0600)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(path+".tmp", path)
+}
+
+// Destroys the droplets, volumes and volume snapshots of this server's targets past their max lifetime
+// Returns whether any of its targets are still going to expire
+func (p *DigitalOceanProvider) ReapExpiredTargets(client *godo.Client, logWriter io.Writer) (bool, error) {
+	serverTag, err := p.getServerTag()
+	if err != nil {
+		return false, err
+	}
+
+	resources, err := util.ListTaggedResources(client, serverTag)
+	if err != nil {
+		return false, err
+	}
+
+	expiring := false
+	expiredTargets := map[string]time.Time{}
+	addIfExpired := func(name string, tags []string) {
+		expiresAt, expires := util.GetExpiry(tags)
+		if !expires {
+			return
+		} else if time.Now().Before(expiresAt) {
+			expiring = true
+			return
+		}
+
+		targetId, ok := util.GetTargetIdFromName(name)
+		if ok {
+			expiredTargets[targetId] = expiresAt
+		}
+	}
+
+	for _, droplet := range resources.Droplets {
+		addIfExpired(droplet.Name, droplet.Tags)
+	}
+	for _, volume := range resources.Volumes {
+		addIfExpired(volume.Name, volume.Tags)
+	}
+	for _, snapshot := range resources.VolumeSnapshots {
+		addIfExpired(snapshot.Name, snapshot.Tags)
+	}
+
+	errs := []error{}
+	for targetId, expiresAt := range expiredTargets {
+		err := p.destroyTargetResources(client, targetId)
+		if err != nil {
+			// Retried on the next run
+			expiring = true
+			errs = append(errs, fmt.Errorf("error reaping target %s: %v", targetId, err))
+			continue
+		}
+
+		logWriter.Write([]byte(fmt.Sprintf("Destroyed droplet, volume and volume snapshot of target %s which expired at %s.\n", targetId, expiresAt.Format(time.RFC3339))))
+	}
+
+	return expiring, errors.Join(errs...)
+}
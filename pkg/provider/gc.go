@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/daytonaio/daytona-provider-digitalocean/pkg/provider/util"
+	"github.com/daytonaio/daytona-provider-digitalocean/pkg/types"
+	"github.com/daytonaio/daytona/pkg/models"
+	"github.com/digitalocean/godo"
+)
+
+// Resources younger than this might belong to a target or image that is still being created
+const orphanGracePeriod = time.Hour
+
+// Finds the droplets, volumes, volume snapshots and firewalls of this server that belong to none of the live targets,
+// using the DIGITALOCEAN_ACCESS_TOKEN environment variable or, if enabled, the doctl config.
+// If includeUntaggedServer is set, daytona tagged resources without a server tag are treated as this server's as well.
+// The orphans are deleted unless dryRun is set and are returned either way.
+func (p *DigitalOceanProvider) CollectOrphanedResources(liveTargetIds []string, includeUntaggedServer bool, dryRun bool, logWriter io.Writer) (*util.TaggedResources, error) {
+	client, err := p.getDoClient(&types.TargetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	serverTag, err := p.getServerTag()
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err := util.ListTaggedResources(client, serverTag)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resources created before they were tagged with their server might belong to any server using the account
+	if includeUntaggedServer {
+		untagged, err := listUntaggedServerResources(client)
+		if err != nil {
+			return nil, err
+		}
+
+		resources.Droplets = append(resources.Droplets, untagged.Droplets...)
+		resources.Volumes = append(resources.Volumes, untagged.Volumes...)
+		resources.VolumeSnapshots = append(resources.VolumeSnapshots, untagged.VolumeSnapshots...)
+	}
+
+	firewalls, err := util.ListFirewalls(client)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceNames, err := util.ListResourceNames(client)
+	if err != nil {
+		return nil, err
+	}
+
+	isOrphan := func(name string, createdAt time.Time) bool {
+		if time.Since(createdAt) < orphanGracePeriod {
+			return false
+		}
+
+		targetId, ok := util.GetTargetIdFromName(name)
+		return ok && !slices.Contains(liveTargetIds, targetId)
+	}
+
+	action := "Deleting"
+	if dryRun {
+		action = "Found"
+	}
+
+	orphans := &util.TaggedResources{}
+	// Target resources share the target's name and are deleted together, image builder droplets on their own
+	orphanedTargetIds := []string{}
+	orphanedBuilderIds := []int{}
+	addOrphanedTarget := func(name string) {
+		targetId, _ := util.GetTargetIdFromName(name)
+		if !slices.Contains(orphanedTargetIds, targetId) {
+			orphanedTargetIds = append(orphanedTargetIds, targetId)
+		}
+	}
+
+	for _, droplet := range resources.Droplets {
+		if !isOrphan(droplet.Name, parseCreatedAt(droplet.Created)) {
+			continue
+		}
+
+		orphans.Droplets = append(orphans.Droplets, droplet)
+		logWriter.Write([]byte(fmt.Sprintf("%s orphaned droplet %s (%d)\n", action, droplet.Name, droplet.ID)))
+
+		if strings.HasPrefix(droplet.Name, "daytona-builder-") {
+			orphanedBuilderIds = append(orphanedBuilderIds, droplet.ID)
+		} else {
+			addOrphanedTarget(droplet.Name)
+		}
+	}
+
+	for _, volume := range resources.Volumes {
+		if !isOrphan(volume.Name, volume.CreatedAt) {
+			continue
+		}
+
+		orphans.Volumes = append(orphans.Volumes, volume)
+		logWriter.Write([]byte(fmt.Sprintf("%s orphaned volume %s (%s)\n", action, volume.Name, volume.ID)))
+		addOrphanedTarget(volume.Name)
+	}
+
+	for _, snapshot := range resources.VolumeSnapshots {
+		if !isOrphan(snapshot.Name, parseCreatedAt(snapshot.Created)) {
+			continue
+		}
+
+		orphans.VolumeSnapshots = append(orphans.VolumeSnapshots, snapshot)
+		logWriter.Write([]byte(fmt.Sprintf("%s orphaned volume snapshot %s (%s)\n", action, snapshot.Name, snapshot.ID)))
+		addOrphanedTarget(snapshot.Name)
+	}
+
+	// Firewalls can not be told apart by server, but every target of any server keeps a droplet, volume or volume snapshot
+	// of the firewall's name. A firewall without them was left behind by a failed creation or removal.
+	for _, firewall := range firewalls {
+		if resourceNames[firewall.Name] || !slices.Equal(firewall.Tags, []string{firewall.Name}) || !isOrphan(firewall.Name, parseCreatedAt(firewall.Created)) {
+			continue
+		}
+
+		orphans.Firewalls = append(orphans.Firewalls, firewall)
+		logWriter.Write([]byte(fmt.Sprintf("%s orphaned firewall %s (%s)\n", action, firewall.Name, firewall.ID)))
+		addOrphanedTarget(firewall.Name)
+	}
+
+	if dryRun {
+		return orphans, nil
+	}
+
+	errs := []error{}
+	for _, dropletId := range orphanedBuilderIds {
+		_, err := client.Droplets.Delete(context.Background(), dropletId)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error deleting image builder droplet %d: %v", dropletId, err))
+		}
+	}
+
+	for _, targetId := range orphanedTargetIds {
+		err := p.destroyTargetResources(client, targetId)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error deleting resources of target %s: %v", targetId, err))
+		}
+	}
+
+	return orphans, errors.Join(errs...)
+}
+
+// Deletes the droplet, volume, volume snapshot, firewall and reserved IP of a target
+// without requiring its config, e.g. when the target is no longer known to Daytona
+func (p *DigitalOceanProvider) destroyTargetResources(client *godo.Client, targetId string) error {
+	target := &models.Target{Id: targetId}
+
+	allocatedIp, err := getAllocatedReservedIp(client, target)
+	if err != nil {
+		return err
+	}
+
+	err = util.DeleteDroplet(client, target, true)
+	if err != nil {
+		return err
+	}
+
+	return releaseReservedIp(client, allocatedIp)
+}
+
+// Returns the daytona tagged droplets, volumes and volume snapshots that carry no server tag
+func listUntaggedServerResources(client *godo.Client) (*util.TaggedResources, error) {
+	resources, err := util.ListTaggedResources(client, "daytona")
+	if err != nil {
+		return nil, err
+	}
+
+	hasServerTag := func(tags []string) bool {
+		return slices.ContainsFunc(tags, func(tag string) bool { return strings.HasPrefix(tag, serverTagPrefix) })
+	}
+
+	untagged := &util.TaggedResources{}
+	for _, droplet := range resources.Droplets {
+		if !hasServerTag(droplet.Tags) {
+			untagged.Droplets = append(untagged.Droplets, droplet)
+		}
+	}
+	for _, volume := range resources.Volumes {
+		if !hasServerTag(volume.Tags) {
+			untagged.Volumes = append(untagged.Volumes, volume)
+		}
+	}
+	for _, snapshot := range resources.VolumeSnapshots {
+		if !hasServerTag(snapshot.Tags) {
+			untagged.VolumeSnapshots = append(untagged.VolumeSnapshots, snapshot)
+		}
+	}
+
+	return untagged, nil
+}
+
+// Resources with an unknown creation time are treated as new so they are never collected
+func parseCreatedAt(createdAt string) time.Time {
+	created, err := time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return time.Now()
+	}
+
+	return created
+}
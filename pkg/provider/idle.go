@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"strconv"
+
+	"github.com/daytonaio/daytona/pkg/agent/ssh/config"
+)
+
+// Lives on the volume so the containers stopped by the idle watcher are started again even if the droplet is recreated
+const idleStoppedContainersFile = "/home/daytona/.daytona-idle-stopped-containers"
+
+// Returns the user data installing a systemd timer that stops all containers and powers off the droplet
+// once there were no SSH or IDE sessions, docker exec calls or CPU load for the idle timeout,
+// and a service starting the stopped containers again once the droplet is powered on
+func getIdleWatcherUserData(idleTimeoutMinutes int) string {
+	return `
+# Install idle watcher
+cat > /usr/local/bin/daytona-idle-watcher << 'EOF'
+#!/bin/bash
+IDLE_TIMEOUT_MINUTES=` + strconv.Itoa(idleTimeoutMinutes) + `
+STOPPED_CONTAINERS_FILE=` + idleStoppedContainersFile + `
+AGENT_SSH_PORT=` + strconv.Itoa(config.SSH_PORT) + `
+# Load average per CPU above which the droplet is considered active
+LOAD_THRESHOLD=0.1
+# Lives in /run so the idle time is reset on every boot
+STATE_FILE=/run/daytona-idle-watcher/last-active
+
+mkdir -p "$(dirname "$STATE_FILE")"
+[ -f "$STATE_FILE" ] || touch "$STATE_FILE"
+
+active=false
+
+# Daytona SSH and IDE sessions are connections to the SSH server of the target's or a workspace's agent,
+# which do not show up in who. The workspace agents run in the network namespaces of their containers.
+agent_ssh_connections() {
+  ss -Htn state established "( sport = :$AGENT_SSH_PORT )"
+  for pid in $(docker ps -q | xargs -r docker inspect -f '{{.State.Pid}}'); do
+    nsenter -t "$pid" -n ss -Htn state established "( sport = :$AGENT_SSH_PORT )"
+  done
+}
+
+if [ -n "$(who)" ] || [ -n "$(agent_ssh_connections 2>/dev/null)" ]; then
+  active=true
+fi
+
+if [ -n "$(docker events --since 2m --until "$(date +%s)" --filter event=exec_start 2>/dev/null)" ]; then
+  active=true
+fi
+
+if awk -v load="$(cut -d ' ' -f 1 /proc/loadavg)" -v cpus="$(nproc)" -v threshold="$LOAD_THRESHOLD" 'BEGIN { exit !(load / cpus >= threshold) }'; then
+  active=true
+fi
+
+if [ "$active" = true ]; then
+  touch "$STATE_FILE"
+  exit 0
+fi
+
+if [ $(( $(date +%s) - $(stat -c %Y "$STATE_FILE") )) -ge $(( IDLE_TIMEOUT_MINUTES * 60 )) ]; then
+  docker ps -q --no-trunc > "$STOPPED_CONTAINERS_FILE.tmp"
+  mv "$STOPPED_CONTAINERS_FILE.tmp" "$STOPPED_CONTAINERS_FILE"
+  xargs -r docker stop < "$STOPPED_CONTAINERS_FILE"
+  shutdown -h now
+fi
+EOF
+chmod +x /usr/local/bin/daytona-idle-watcher
+
+cat > /etc/systemd/system/daytona-idle-watcher.service << 'EOF'
+[Unit]
+Description=Daytona Idle Watcher
+After=docker.service
+
+[Service]
+Type=oneshot
+ExecStart=/usr/local/bin/daytona-idle-watcher
+EOF
+
+cat > /etc/systemd/system/daytona-idle-watcher.timer << 'EOF'
+[Unit]
+Description=Run the Daytona Idle Watcher every minute
+
+[Timer]
+OnBootSec=1min
+OnUnitActiveSec=1min
+
+[Install]
+WantedBy=timers.target
+EOF
+
+cat > /usr/local/bin/daytona-idle-resume << 'EOF'
+#!/bin/bash
+STOPPED_CONTAINERS_FILE=` + idleStoppedContainersFile + `
+
+[ -f "$STOPPED_CONTAINERS_FILE" ] || exit 0
+
+# Containers removed in the meantime are skipped
+while read -r container; do
+  docker start "$container" || echo "Failed to start container $container" >&2
+done < "$STOPPED_CONTAINERS_FILE"
+
+rm -f "$STOPPED_CONTAINERS_FILE"
+EOF
+chmod +x /usr/local/bin/daytona-idle-resume
+
+cat > /etc/systemd/system/daytona-idle-resume.service << 'EOF'
+[Unit]
+Description=Start the containers stopped by the Daytona Idle Watcher
+After=docker.service
+Requires=docker.service
+RequiresMountsFor=/home/daytona
+
+[Service]
+Type=oneshot
+RemainAfterExit=true
+ExecStart=/usr/local/bin/daytona-idle-resume
+
+[Install]
+WantedBy=multi-user.target
+EOF
+
+systemctl daemon-reload
+systemctl enable --now daytona-idle-watcher.timer
+# Also covers a droplet recreated after its containers were stopped
+systemctl enable daytona-idle-resume.service
+systemctl start --no-block daytona-idle-resume.service
+`
+}
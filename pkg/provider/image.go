@@ -0,0 +1,226 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+
+	log_writers "github.com/daytonaio/daytona-provider-digitalocean/internal/log"
+	"github.com/daytonaio/daytona-provider-digitalocean/pkg/provider/util"
+	"github.com/daytonaio/daytona-provider-digitalocean/pkg/types"
+	"github.com/digitalocean/godo"
+	"github.com/google/uuid"
+)
+
+// Holds the Daytona version the agent in a baked image was installed for
+const imageVersionFile = "/etc/daytona-image-version"
+
+const installDockerScript = `# Check if docker is installed
+if ! command -v docker &> /dev/null; then
+  curl -fsSL https://get.docker.com | bash
+fi
+`
+
+// Creates a snapshot of the target's image with Docker and the Daytona agent preinstalled.
+// The snapshot is versioned with the Daytona version and is picked up by all targets using the same image.
+func (p *DigitalOceanProvider) bakeImage(client *godo.Client, targetOptions *types.TargetOptions, sizes []godo.Size, logWriter io.Writer) (*godo.Image, error) {
+	imageName := p.getBakedImageName(targetOptions.Image)
+
+	image, err := util.GetUserImageByName(client, imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	if image != nil {
+		if util.IsImageInRegion(image, targetOptions.Region) {
+			return image, nil
+		}
+
+		logWriter.Write([]byte(fmt.Sprintf("Transferring image %s to region %s...\n", imageName, targetOptions.Region)))
+
+		action, _, err := client.ImageActions.Transfer(context.Background(), image.ID, &godo.ActionRequest{
+			"type":   "transfer",
+			"region": targetOptions.Region,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error transferring image: %v", err)
+		}
+
+		err = util.WaitForAction(client, action)
+		if err != nil {
+			return nil, err
+		}
+
+		image, _, err = client.Images.GetByID(context.Background(), image.ID)
+		return image, err
+	}
+
+	baseImage, err := util.GetDropletCreateImage(client, targetOptions.Image, targetOptions.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	// The snapshot can only be used for sizes with at least the disk of the builder droplet
+	builderSize, err := util.FindSmallestSize(sizes, targetOptions.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	logWriter.Write([]byte("Creating image builder droplet...\n"))
+
+	droplet, _, err := client.Droplets.Create(context.Background(), &godo.DropletCreateRequest{
+		Name:     fmt.Sprintf("daytona-builder-%s", uuid.NewString()[:8]),
+		Region:   targetOptions.Region,
+		Size:     builderSize.Slug,
+		Image:    baseImage,
+		UserData: p.getBakeUserData(),
+		Tags:     p.getProviderTags(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating image builder droplet: %v", err)
+	}
+	defer func() {
+		_, err := client.Droplets.Delete(context.Background(), droplet.ID)
+		if err != nil {
+			logWriter.Write([]byte("Failed to delete image builder droplet: " + err.Error() + "\n"))
+		}
+	}()
+
+	// The builder droplet powers itself off once provisioning is done
+	provisioningSpinner := log_writers.ShowSpinner(logWriter, "Provisioning image builder droplet", "Image builder droplet provisioned")
+	_, err = util.WaitForDropletStatus(client, droplet.ID, "off", 20*time.Minute)
+	close(provisioningSpinner)
+	if err != nil {
+		return nil, err
+	}
+
+	logWriter.Write([]byte(fmt.Sprintf("Creating image %s...\n", imageName)))
+
+	action, _, err := client.DropletActions.Snapshot(context.Background(), droplet.ID, imageName)
+	if err != nil {
+		return nil, fmt.Errorf("error creating snapshot: %v", err)
+	}
+
+	err = util.WaitForAction(client, action)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err = util.GetUserImageByName(client, imageName)
+	if err != nil {
+		return nil, err
+	} else if image == nil {
+		return nil, fmt.Errorf("image %s not found after snapshot", imageName)
+	}
+
+	err = util.TagResources(client, p.getProviderTags(), godo.Resource{ID: strconv.Itoa(image.ID), Type: godo.ImageResourceType})
+	if err != nil {
+		return nil, err
+	}
+
+	logWriter.Write([]byte("Image created.\n"))
+
+	// Only fails the cleanup, the new image can be used regardless
+	err = p.deleteOutdatedBakedImages(client, targetOptions.Image, logWriter)
+	if err != nil {
+		logWriter.Write([]byte("Failed to delete outdated images: " + err.Error() + "\n"))
+	}
+
+	return image, nil
+}
+
+// Returns the baked image for the target if one exists and fits the target's size, otherwise the configured image
+func (p *DigitalOceanProvider) getDropletImage(client *godo.Client, targetOptions *types.TargetOptions, sizes []godo.Size, logWriter io.Writer) (godo.DropletCreateImage, error) {
+	size, err := util.FindSize(sizes, targetOptions.Size)
+	if err != nil {
+		return godo.DropletCreateImage{}, err
+	}
+
+	var image *godo.Image
+	if targetOptions.BakeImage {
+		image, err = p.bakeImage(client, targetOptions, sizes, logWriter)
+		if err != nil {
+			return godo.DropletCreateImage{}, err
+		}
+	} else {
+		image, err = util.GetUserImageByName(client, p.getBakedImageName(targetOptions.Image))
+		if err != nil {
+			return godo.DropletCreateImage{}, err
+		}
+
+		if image != nil && !util.IsImageInRegion(image, targetOptions.Region) {
+			image = nil
+		}
+	}
+
+	// Images baked on a larger size, e.g. by older versions of the provider, can not be used for smaller sizes
+	if image != nil && image.MinDiskSize > size.Disk {
+		logWriter.Write([]byte(fmt.Sprintf("Image %s requires a disk of at least %d GB but size %s has %d GB, using image %s instead.\n", image.Name, image.MinDiskSize, size.Slug, size.Disk, targetOptions.Image)))
+		image = nil
+	}
+
+	if image != nil {
+		logWriter.Write([]byte(fmt.Sprintf("Using image %s.\n", image.Name)))
+		return godo.DropletCreateImage{ID: image.ID}, nil
+	}
+
+	return util.GetDropletCreateImage(client, targetOptions.Image, targetOptions.Region)
+}
+
+func (p *DigitalOceanProvider) getBakedImageName(image string) string {
+	return util.GetBakedImageName(*p.DaytonaVersion, image)
+}
+
+// Deletes this server's images baked from the same base image for other Daytona versions, which are no longer used
+func (p *DigitalOceanProvider) deleteOutdatedBakedImages(client *godo.Client, image string, logWriter io.Writer) error {
+	serverTag, err := p.getServerTag()
+	if err != nil {
+		return err
+	}
+
+	userImages, err := util.ListUserImages(client)
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, userImage := range userImages {
+		version, baked := util.GetBakedImageVersion(userImage.Name, image)
+		if !baked || version == *p.DaytonaVersion || !slices.Contains(userImage.Tags, serverTag) {
+			continue
+		}
+
+		_, err := client.Images.Delete(context.Background(), userImage.ID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error deleting image %s: %v", userImage.Name, err))
+			continue
+		}
+
+		logWriter.Write([]byte(fmt.Sprintf("Deleted image %s baked for Daytona %s.\n", userImage.Name, version)))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p *DigitalOceanProvider) getBakeUserData() string {
+	userData := "#!/bin/bash\nset -e\n\n" + installDockerScript
+
+	if p.ApiKey != nil {
+		userData += fmt.Sprintf(`
+curl -sfL -H "Authorization: Bearer %s" %s | bash
+echo '%s' > %s
+`, *p.ApiKey, *p.DaytonaDownloadUrl, *p.DaytonaVersion, imageVersionFile)
+	}
+
+	userData += `
+# Reset cloud-init so that user data runs on droplets created from the snapshot
+cloud-init clean --logs
+shutdown -h now
+`
+
+	return userData
+}
@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/daytonaio/daytona-provider-digitalocean/pkg/types"
+	"github.com/daytonaio/daytona/pkg/provider"
+)
+
+// Name of the file under the provider's base path that replaces the default presets
+const presetsFileName = "presets.json"
+
+type presetTargetConfig struct {
+	Name    string          `json:"name"`
+	Options json.RawMessage `json:"options"`
+}
+
+var defaultPresets = map[string]types.TargetOptions{
+	"digitalocean-small": {
+		Region:   "fra1",
+		Size:     "s-2vcpu-4gb",
+		DiskSize: 20,
+		Image:    "docker-20-04",
+	},
+	"digitalocean-medium": {
+		Region:   "fra1",
+		Size:     "s-4vcpu-8gb",
+		DiskSize: 50,
+		Image:    "docker-20-04",
+	},
+	"digitalocean-large": {
+		Region:   "fra1",
+		Size:     "s-8vcpu-16gb",
+		DiskSize: 100,
+		Image:    "docker-20-04",
+	},
+	"digitalocean-cpu-optimized": {
+		Region:   "fra1",
+		Size:     "c-4",
+		DiskSize: 50,
+		Image:    "docker-20-04",
+	},
+}
+
+var defaultPresetOrder = []string{"digitalocean-small", "digitalocean-medium", "digitalocean-large", "digitalocean-cpu-optimized"}
+
+func (p *DigitalOceanProvider) getPresetTargetConfigs() ([]provider.TargetConfig, error) {
+	presets, err := p.readPresetsFile()
+	if err != nil || presets != nil {
+		return presets, err
+	}
+
+	presets = []provider.TargetConfig{}
+	for _, name := range defaultPresetOrder {
+		options, err := json.Marshal(defaultPresets[name])
+		if err != nil {
+			return nil, err
+		}
+
+		presets = append(presets, provider.TargetConfig{
+			Name:    name,
+			Options: string(options),
+		})
+	}
+
+	return presets, nil
+}
+
+// Returns nil if the presets file does not exist
+// Options in the file can either be a JSON object or a JSON encoded string
+func (p *DigitalOceanProvider) readPresetsFile() ([]provider.TargetConfig, error) {
+	if p.BasePath == nil {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(filepath.Join(*p.BasePath, presetsFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var presetsFile []presetTargetConfig
+	err = json.Unmarshal(content, &presetsFile)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", presetsFileName, err)
+	}
+
+	presets := []provider.TargetConfig{}
+	for _, preset := range presetsFile {
+		options := string(preset.Options)
+
+		var optionsString string
+		if json.Unmarshal(preset.Options, &optionsString) == nil {
+			options = optionsString
+		}
+
+		_, err = types.ParseTargetOptions(options)
+		if err != nil {
+			return nil, fmt.Errorf("invalid options for preset %s: %v", preset.Name, err)
+		}
+
+		presets = append(presets, provider.TargetConfig{
+			Name:    preset.Name,
+			Options: options,
+		})
+	}
+
+	return presets, nil
+}
@@ -0,0 +1,94 @@
+package provider_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/daytonaio/daytona-provider-digitalocean/pkg/provider"
+	"github.com/daytonaio/daytona-provider-digitalocean/pkg/types"
+)
+
+func TestGetPresetTargetConfigs(t *testing.T) {
+	tests := []struct {
+		name        string
+		presetsFile string
+		wantNames   []string
+		wantRegions []string
+		wantErr     bool
+	}{
+		{
+			name:        "default presets",
+			wantNames:   []string{"digitalocean-small", "digitalocean-medium", "digitalocean-large", "digitalocean-cpu-optimized"},
+			wantRegions: []string{"fra1", "fra1", "fra1", "fra1"},
+		},
+		{
+			name:        "options object",
+			presetsFile: `[{"name": "team-default", "options": {"Region": "nyc3", "Size": "s-4vcpu-8gb", "Disk Size": 50, "Image": "docker-20-04"}}]`,
+			wantNames:   []string{"team-default"},
+			wantRegions: []string{"nyc3"},
+		},
+		{
+			name:        "options string",
+			presetsFile: `[{"name": "small", "options": "{\"Region\": \"ams3\", \"Size\": \"s-1vcpu-2gb\"}"}, {"name": "large", "options": {"Region": "sfo3"}}]`,
+			wantNames:   []string{"small", "large"},
+			wantRegions: []string{"ams3", "sfo3"},
+		},
+		{
+			name:        "empty list",
+			presetsFile: `[]`,
+			wantNames:   []string{},
+			wantRegions: []string{},
+		},
+		{
+			name:        "invalid json",
+			presetsFile: `{"name": "team-default"}`,
+			wantErr:     true,
+		},
+		{
+			name:        "invalid options",
+			presetsFile: `[{"name": "team-default", "options": {"Stop Strategy": "hibernate"}}]`,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			basePath := t.TempDir()
+			if tt.presetsFile != "" {
+				err := os.WriteFile(filepath.Join(basePath, "presets.json"), []byte(tt.presetsFile), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			p := &provider.DigitalOceanProvider{BasePath: &basePath}
+			presets, err := p.GetPresetTargetConfigs()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetPresetTargetConfigs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(*presets) != len(tt.wantNames) {
+				t.Fatalf("GetPresetTargetConfigs() returned %d presets, want %d", len(*presets), len(tt.wantNames))
+			}
+
+			for i, preset := range *presets {
+				if preset.Name != tt.wantNames[i] {
+					t.Errorf("preset %d name = %s, want %s", i, preset.Name, tt.wantNames[i])
+				}
+
+				targetOptions, err := types.ParseTargetOptions(preset.Options)
+				if err != nil {
+					t.Fatalf("preset %s has invalid options: %v", preset.Name, err)
+				}
+
+				if targetOptions.Region != tt.wantRegions[i] {
+					t.Errorf("preset %s region = %s, want %s", preset.Name, targetOptions.Region, tt.wantRegions[i])
+				}
+			}
+		})
+	}
+}
@@ -2,16 +2,22 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
+	"sync"
 	"time"
 
 	internal "github.com/daytonaio/daytona-provider-digitalocean/internal"
 	logwriters "github.com/daytonaio/daytona-provider-digitalocean/internal/log"
+	"github.com/daytonaio/daytona-provider-digitalocean/pkg/provider/util"
 	"github.com/daytonaio/daytona-provider-digitalocean/pkg/types"
 	"github.com/daytonaio/daytona/pkg/agent/ssh/config"
 	"github.com/daytonaio/daytona/pkg/docker"
@@ -22,13 +28,17 @@ import (
 	"github.com/daytonaio/daytona/pkg/tailscale"
 	"github.com/docker/docker/client"
 	"github.com/google/uuid"
-	"golang.org/x/oauth2"
 	"tailscale.com/tsnet"
 
 	"github.com/daytonaio/daytona/pkg/provider"
 	"github.com/digitalocean/godo"
 )
 
+const dockerSocketPath = "/var/run/docker.sock"
+
+// Prefix of the tag distinguishing the resources of Daytona servers sharing an account
+const serverTagPrefix = "daytona-server:"
+
 type DigitalOceanProvider struct {
 	BasePath           *string
 	DaytonaDownloadUrl *string
@@ -43,6 +53,10 @@ type DigitalOceanProvider struct {
 	NetworkKey         *string
 
 	tsnetConn *tsnet.Server
+
+	// Reapers of expired targets by the hash of the token they authenticate with
+	reapers      map[string]*reaper
+	reapersMutex sync.Mutex
 }
 
 func (p *DigitalOceanProvider) Initialize(req provider.InitializeProviderRequest) (*provider_util.Empty, error) {
@@ -58,6 +72,18 @@ func (p *DigitalOceanProvider) Initialize(req provider.InitializeProviderRequest
 	p.TargetLogsDir = &req.TargetLogsDir
 	p.NetworkKey = &req.NetworkKey
 
+	// Targets with a max lifetime are reaped with the default token and the token references they were reaped with before the restart
+	_, err := p.getDoClient(&types.TargetOptions{})
+	if err == nil {
+		p.startReaper(&types.TargetOptions{})
+	}
+
+	err = p.startPersistedReapers()
+	if err != nil {
+		logWriter := &logwriters.InfoLogWriter{}
+		logWriter.Write([]byte("Failed to start reapers: " + err.Error() + "\n"))
+	}
+
 	return new(provider_util.Empty), nil
 }
 
@@ -72,20 +98,52 @@ func (p *DigitalOceanProvider) GetInfo() (models.ProviderInfo, error) {
 }
 
 func (p *DigitalOceanProvider) GetPresetTargetConfigs() (*[]provider.TargetConfig, error) {
-	return new([]provider.TargetConfig), nil
+	presets, err := p.getPresetTargetConfigs()
+	if err != nil {
+		return nil, err
+	}
+
+	return &presets, nil
 }
 
 func (p *DigitalOceanProvider) GetTargetProviderMetadata(targetReq *provider.TargetRequest) (string, error) {
 	logWriter, cleanupFunc := p.getTargetLogWriter(targetReq.Target.Id, targetReq.Target.Name)
 	defer cleanupFunc()
 
-	dockerClient, err := p.getDockerClient(targetReq.Target.Id)
+	targetOptions, err := types.ParseTargetOptions(targetReq.Target.TargetConfig.Options)
 	if err != nil {
-		logWriter.Write([]byte("Failed to get docker client: " + err.Error() + "\n"))
+		logWriter.Write([]byte("Error parsing target config options: " + err.Error() + "\n"))
 		return "", err
 	}
 
-	return dockerClient.GetTargetProviderMetadata(targetReq.Target)
+	// The metadata is still returned without the cost if the API can not be reached
+	costEstimate, err := p.estimateCost(targetOptions)
+	if err != nil {
+		logWriter.Write([]byte("Failed to estimate cost: " + err.Error() + "\n"))
+	}
+
+	metadata, err := json.Marshal(types.TargetMetadata{
+		EstimatedCost: costEstimate,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(metadata), nil
+}
+
+func (p *DigitalOceanProvider) estimateCost(targetOptions *types.TargetOptions) (*types.CostEstimate, error) {
+	client, err := p.getDoClient(targetOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	sizes, err := util.ListSizes(client)
+	if err != nil {
+		return nil, err
+	}
+
+	return util.EstimateCost(sizes, targetOptions)
 }
 
 func (p *DigitalOceanProvider) GetWorkspaceProviderMetadata(workspaceReq *provider.WorkspaceRequest) (string, error) {
@@ -102,25 +160,56 @@ func (p *DigitalOceanProvider) GetWorkspaceProviderMetadata(workspaceReq *provid
 }
 
 func (p *DigitalOceanProvider) getDoClient(targetOptions *types.TargetOptions) (*godo.Client, error) {
-	doToken := targetOptions.AuthToken
-
-	if doToken == nil {
-		envToken := os.Getenv("DIGITALOCEAN_ACCESS_TOKEN")
-		// Get the DigitalOcean token from the environment variable
-		if envToken == "" {
-			return nil, errors.New("DigitalOcean Token not found")
-		}
+	// The token is resolved again when the API rejects it, e.g. after it was rotated
+	tokenSource := util.NewRotatingTokenSource(func() (string, error) {
+		return p.getDoToken(targetOptions)
+	})
 
-		doToken = &envToken
+	_, err := tokenSource.Token()
+	if err != nil {
+		return nil, err
 	}
 
 	// Create a new DigitalOcean client
-	oauthClient := oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(&oauth2.Token{AccessToken: *doToken}))
-	client := godo.NewClient(oauthClient)
+	client := godo.NewClient(&http.Client{Transport: &util.TokenRefreshTransport{Source: tokenSource}})
 
 	return client, nil
 }
 
+// Returns the target's Auth Token, falling back to the DIGITALOCEAN_ACCESS_TOKEN environment variable
+// and the active doctl context. Token references like file:<path> are resolved.
+func (p *DigitalOceanProvider) getDoToken(targetOptions *types.TargetOptions) (string, error) {
+	config, err := p.readConfig()
+	if err != nil {
+		return "", err
+	}
+
+	if targetOptions.AuthToken != nil && *targetOptions.AuthToken != "" {
+		if util.IsTokenReference(*targetOptions.AuthToken) && !config.AllowTokenReferences {
+			return "", fmt.Errorf("token references in the Auth Token option are disabled, set allowTokenReferences in %s to enable them", configFileName)
+		}
+
+		return util.ResolveToken(*targetOptions.AuthToken)
+	}
+
+	// The environment is controlled by the server's administrator, so references are always resolved
+	envToken := os.Getenv("DIGITALOCEAN_ACCESS_TOKEN")
+	if envToken != "" {
+		return util.ResolveToken(envToken)
+	}
+
+	if !config.UseDoctlConfig {
+		return "", util.ErrTokenNotFound
+	}
+
+	doctlToken, err := util.ReadDoctlToken("")
+	if err != nil || doctlToken == "" {
+		return "", util.ErrTokenNotFound
+	}
+
+	return doctlToken, nil
+}
+
 func (p *DigitalOceanProvider) getTsnetConn() (*tsnet.Server, error) {
 	if p.tsnetConn == nil {
 		tsnetConn, err := tailscale.GetConnection(&tailscale.TsnetConnConfig{
@@ -140,12 +229,7 @@ func (p *DigitalOceanProvider) getTsnetConn() (*tsnet.Server, error) {
 }
 
 func (p *DigitalOceanProvider) getDockerClient(targetId string) (docker.IDockerClient, error) {
-	tsnetConn, err := p.getTsnetConn()
-	if err != nil {
-		return nil, err
-	}
-
-	cli, err := client.NewClientWithOpts(client.WithDialContext(tsnetConn.Dial), client.WithHost(fmt.Sprintf("http://%s:2375", targetId)), client.WithAPIVersionNegotiation())
+	cli, err := client.NewClientWithOpts(client.WithDialContext(p.dialDockerSocket(targetId)), client.WithHost(fmt.Sprintf("http://%s", targetId)), client.WithAPIVersionNegotiation())
 	if err != nil {
 		return nil, err
 	}
@@ -160,6 +244,25 @@ func (p *DigitalOceanProvider) getDockerClient(targetId string) (docker.IDockerC
 	}), nil
 }
 
+// The Docker daemon is not reachable over the network so connections are
+// forwarded to its unix socket through the agent's SSH server
+func (p *DigitalOceanProvider) dialDockerSocket(targetId string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		sshClient, err := p.getSshClient(targetId)
+		if err != nil {
+			return nil, err
+		}
+
+		conn, err := sshClient.DialContext(ctx, "unix", dockerSocketPath)
+		if err != nil {
+			sshClient.Close()
+			return nil, err
+		}
+
+		return &sshForwardedConn{Conn: conn, sshClient: sshClient}, nil
+	}
+}
+
 func (p *DigitalOceanProvider) waitForDial(targetId string, dialTimeout time.Duration) error {
 	tsnetConn, err := p.getTsnetConn()
 	if err != nil {
@@ -195,6 +298,49 @@ func (p *DigitalOceanProvider) getSshClient(workspaceId string) (*ssh.Client, er
 	})
 }
 
+// Returns the tags applied to every resource created for the target
+func (p *DigitalOceanProvider) getTargetTags(target *models.Target, targetOptions *types.TargetOptions) ([]string, error) {
+	tags := append(p.getProviderTags(), util.SanitizeTag("daytona-target:", target.Id), util.SanitizeTag("daytona-target-name:", target.Name))
+
+	if targetOptions.Tags != nil {
+		customTags, err := util.ParseTags(*targetOptions.Tags)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, customTags...)
+	}
+
+	return tags, nil
+}
+
+// Returns the tags applied to resources shared between targets
+func (p *DigitalOceanProvider) getProviderTags() []string {
+	tags := []string{"daytona"}
+
+	serverTag, err := p.getServerTag()
+	if err == nil {
+		tags = append(tags, serverTag)
+	}
+
+	return tags
+}
+
+// Returns the tag distinguishing the resources of this Daytona server from those of other servers using the same account
+func (p *DigitalOceanProvider) getServerTag() (string, error) {
+	if p.ServerUrl == nil {
+		return "", errors.New("ServerUrl not set. Did you forget to call Initialize")
+	}
+
+	serverUrl, err := url.Parse(*p.ServerUrl)
+	if err != nil {
+		return "", fmt.Errorf("invalid server url %s: %v", *p.ServerUrl, err)
+	} else if serverUrl.Hostname() == "" {
+		return "", fmt.Errorf("server url %s has no host", *p.ServerUrl)
+	}
+
+	return util.SanitizeTag(serverTagPrefix, serverUrl.Hostname()), nil
+}
+
 func (p *DigitalOceanProvider) getWorkspaceDir(workspaceReq *provider.WorkspaceRequest) string {
 	return path.Join(
 		p.getTargetDir(workspaceReq.Workspace.TargetId),
@@ -205,6 +351,69 @@ func (p *DigitalOceanProvider) getWorkspaceDir(workspaceReq *provider.WorkspaceR
 
 func (a *DigitalOceanProvider) CheckRequirements() (*[]provider.RequirementStatus, error) {
 	results := []provider.RequirementStatus{}
+
+	client, err := a.getDoClient(&types.TargetOptions{})
+	if err != nil {
+		results = append(results, provider.RequirementStatus{
+			Name:   "DigitalOcean token",
+			Met:    false,
+			Reason: "Failed to get DigitalOcean token from the DIGITALOCEAN_ACCESS_TOKEN environment variable or, if useDoctlConfig is set, the doctl config, target configs must set the Auth Token option: " + err.Error(),
+		})
+		return &results, nil
+	}
+
+	results = append(results, provider.RequirementStatus{
+		Name:   "DigitalOcean token",
+		Met:    true,
+		Reason: "DigitalOcean token found",
+	})
+
+	account, _, err := client.Account.Get(context.Background())
+	if err != nil {
+		results = append(results, provider.RequirementStatus{
+			Name:   "DigitalOcean authentication",
+			Met:    false,
+			Reason: "Failed to authenticate with DigitalOcean: " + err.Error(),
+		})
+		return &results, nil
+	}
+
+	results = append(results, provider.RequirementStatus{
+		Name:   "DigitalOcean authentication",
+		Met:    true,
+		Reason: "Authenticated with DigitalOcean as " + account.Email,
+	})
+
+	accountStatus := provider.RequirementStatus{
+		Name:   "DigitalOcean account status",
+		Met:    account.Status == "active",
+		Reason: "DigitalOcean account is " + account.Status,
+	}
+	if account.StatusMessage != "" {
+		accountStatus.Reason += ": " + account.StatusMessage
+	}
+	results = append(results, accountStatus)
+
+	dropletLimit := provider.RequirementStatus{Name: "DigitalOcean droplet limit"}
+	dropletCount, err := util.CountDroplets(client)
+	if err != nil {
+		dropletLimit.Reason = "Failed to get droplet usage: " + err.Error()
+	} else {
+		dropletLimit.Met = dropletCount < account.DropletLimit
+		dropletLimit.Reason = fmt.Sprintf("Using %d of %d droplets", dropletCount, account.DropletLimit)
+	}
+	results = append(results, dropletLimit)
+
+	volumeLimit := provider.RequirementStatus{Name: "DigitalOcean volume limit"}
+	volumeCount, err := util.CountVolumes(client)
+	if err != nil {
+		volumeLimit.Reason = "Failed to get volume usage: " + err.Error()
+	} else {
+		volumeLimit.Met = volumeCount < account.VolumeLimit
+		volumeLimit.Reason = fmt.Sprintf("Using %d of %d volumes", volumeCount, account.VolumeLimit)
+	}
+	results = append(results, volumeLimit)
+
 	return &results, nil
 }
 
@@ -253,3 +462,13 @@ func (p *DigitalOceanProvider) getTargetLogWriter(targetId, targetName string) (
 
 	return logWriter, cleanupFunc
 }
+
+type sshForwardedConn struct {
+	net.Conn
+	sshClient *ssh.Client
+}
+
+func (c *sshForwardedConn) Close() error {
+	defer c.sshClient.Close()
+	return c.Conn.Close()
+}
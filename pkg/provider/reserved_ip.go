@@ -0,0 +1,191 @@
+package provider
+
+import (
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/daytonaio/daytona-provider-digitalocean/pkg/provider/util"
+	"github.com/daytonaio/daytona-provider-digitalocean/pkg/types"
+	"github.com/daytonaio/daytona/pkg/models"
+	"github.com/digitalocean/godo"
+)
+
+const newReservedIp = "new"
+
+// Assigns the target's reserved IP to the droplet. Reserved IPs allocated by the provider are recorded
+// in a tag on the droplet and volume, which the volume snapshot keeps, so they survive droplet recreations
+// and are only released when the target is destroyed.
+func (p *DigitalOceanProvider) assignReservedIp(client *godo.Client, target *models.Target, targetOptions *types.TargetOptions, droplet *godo.Droplet, logWriter io.Writer) error {
+	if targetOptions.ReservedIp == nil || *targetOptions.ReservedIp == "" {
+		return nil
+	}
+
+	ip := *targetOptions.ReservedIp
+	if ip == newReservedIp {
+		allocatedIp, err := getAllocatedReservedIp(client, target)
+		if err != nil {
+			return err
+		}
+
+		if allocatedIp == "" {
+			allocatedIp, err = allocateReservedIp(client, target, targetOptions, droplet)
+			if err != nil {
+				return err
+			}
+		}
+
+		ip = allocatedIp
+	}
+
+	reservedIp, err := util.GetReservedIp(client, ip, droplet)
+	if err != nil {
+		return err
+	}
+
+	// Already assigned when the droplet was created or last started
+	if reservedIp.Droplet != nil && reservedIp.Droplet.ID == droplet.ID {
+		return nil
+	}
+
+	err = util.AssignReservedIp(client, reservedIp, droplet)
+	if err != nil {
+		return err
+	}
+
+	logWriter.Write([]byte("Reserved IP " + ip + " assigned.\n"))
+
+	return nil
+}
+
+func allocateReservedIp(client *godo.Client, target *models.Target, targetOptions *types.TargetOptions, droplet *godo.Droplet) (string, error) {
+	var project *godo.Project
+	if targetOptions.Project != nil && *targetOptions.Project != "" {
+		var err error
+		project, err = util.GetProject(client, *targetOptions.Project)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	volume, err := util.GetVolumeByName(client, util.GetDropletName(target))
+	if err != nil {
+		return "", err
+	}
+
+	reservedIp, err := util.CreateReservedIp(client, targetOptions.Region, project)
+	if err != nil {
+		return "", err
+	}
+
+	resources := []godo.Resource{{ID: strconv.Itoa(droplet.ID), Type: godo.DropletResourceType}}
+	if volume != nil {
+		resources = append(resources, godo.Resource{ID: volume.ID, Type: godo.VolumeResourceType})
+	}
+
+	err = util.TagResources(client, []string{util.GetReservedIpTag(reservedIp.IP)}, resources...)
+	if err != nil {
+		// An untagged reserved IP could never be released
+		deleteErr := util.DeleteReservedIp(client, reservedIp.IP)
+		if deleteErr != nil {
+			return "", errors.Join(err, deleteErr)
+		}
+
+		return "", err
+	}
+
+	return reservedIp.IP, nil
+}
+
+// Releases the reserved IP allocated for the target, which must be looked up before its resources are deleted
+func releaseReservedIp(client *godo.Client, allocatedIp string) error {
+	if allocatedIp == "" {
+		return nil
+	}
+
+	return util.DeleteReservedIp(client, allocatedIp)
+}
+
+// Returns the reserved IP recorded on the target's volume, volume snapshot or droplet, empty if none was allocated
+// Reserved IPs set by address are never recorded, so they are kept when the target is destroyed
+func getAllocatedReservedIp(client *godo.Client, target *models.Target) (string, error) {
+	name := util.GetDropletName(target)
+
+	volume, err := util.GetVolumeByName(client, name)
+	if err != nil {
+		return "", err
+	} else if volume != nil {
+		if ip := util.GetReservedIpFromTags(volume.Tags); ip != "" {
+			return ip, nil
+		}
+	}
+
+	snapshot, err := util.GetVolumeSnapshotByName(client, name)
+	if err != nil {
+		return "", err
+	} else if snapshot != nil {
+		if ip := util.GetReservedIpFromTags(snapshot.Tags); ip != "" {
+			return ip, nil
+		}
+	}
+
+	droplet, err := util.GetDroplet(client, name)
+	if errors.Is(err, util.ErrDropletNotFound) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	return util.GetReservedIpFromTags(droplet.Tags), nil
+}
+
+// Returns the user data installing a service that routes outbound traffic through the droplet's anchor gateway,
+// without which requests leave from the droplet's own public IP instead of the reserved IP.
+// The route is not persistent, so the service sets it on every boot once the reserved IP is assigned.
+func getReservedIpRouteUserData() string {
+	return `
+# Install reserved IP route
+cat > /usr/local/bin/daytona-reserved-ip-route << 'EOF'
+#!/bin/bash
+METADATA_URL=http://169.254.169.254/metadata/v1
+
+# Traffic through the anchor gateway is dropped until the reserved IP is assigned, which happens after the droplet booted
+for i in $(seq 1 180); do
+  if [ "$(curl -sf $METADATA_URL/reserved_ip/ipv4/active)" = "true" ]; then
+    gateway=$(curl -sf $METADATA_URL/interfaces/public/0/anchor_ipv4/gateway)
+    break
+  fi
+  sleep 5
+done
+
+if [ -z "$gateway" ]; then
+  echo "Reserved IP not assigned, keeping the default route" >&2
+  exit 1
+fi
+
+interface=$(ip route show default | awk '{ print $5; exit }')
+ip route replace default via "$gateway" dev "$interface"
+EOF
+chmod +x /usr/local/bin/daytona-reserved-ip-route
+
+cat > /etc/systemd/system/daytona-reserved-ip-route.service << 'EOF'
+[Unit]
+Description=Route outbound traffic through the Daytona target's reserved IP
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+Type=oneshot
+RemainAfterExit=true
+ExecStart=/usr/local/bin/daytona-reserved-ip-route
+
+[Install]
+WantedBy=multi-user.target
+EOF
+
+systemctl daemon-reload
+systemctl enable daytona-reserved-ip-route.service
+# Waits for the reserved IP, which is only assigned once the droplet is active
+systemctl start --no-block daytona-reserved-ip-route.service
+`
+}
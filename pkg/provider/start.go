@@ -2,11 +2,17 @@ package provider
 
 import (
 	"errors"
+	"fmt"
+	"io"
 	"time"
 
+	log_writers "github.com/daytonaio/daytona-provider-digitalocean/internal/log"
+	"github.com/daytonaio/daytona-provider-digitalocean/pkg/provider/util"
 	"github.com/daytonaio/daytona-provider-digitalocean/pkg/types"
 	"github.com/daytonaio/daytona/pkg/docker"
+	"github.com/daytonaio/daytona/pkg/models"
 	provider_util "github.com/daytonaio/daytona/pkg/provider/util"
+	"github.com/digitalocean/godo"
 
 	"github.com/daytonaio/daytona/pkg/provider"
 )
@@ -27,21 +33,186 @@ func (p *DigitalOceanProvider) StartTarget(targetReq *provider.TargetRequest) (*
 		return nil, err
 	}
 
-	_, err = p.createDroplet(client, targetReq.Target, targetOptions, logWriter)
+	existingDroplet, err := util.GetDroplet(client, util.GetDropletName(targetReq.Target))
+	if err != nil && !errors.Is(err, util.ErrDropletNotFound) {
+		logWriter.Write([]byte("Failed to get droplet: " + err.Error() + "\n"))
+		return nil, err
+	}
+
+	volume, err := util.GetVolumeByName(client, util.GetDropletName(targetReq.Target))
+	if err != nil {
+		logWriter.Write([]byte("Failed to get volume: " + err.Error() + "\n"))
+		return nil, err
+	}
+
+	recreate := existingDroplet == nil
+	resize := !recreate && util.GetDropletSize(existingDroplet) != targetOptions.Size
+	growVolume := volume != nil && volume.SizeGigaBytes < int64(targetOptions.DiskSize)
+
+	// Starting a target as it is neither needs the sizes nor can it exceed the budget
+	var sizes []godo.Size
+	if recreate || resize || growVolume {
+		sizes, err = util.ListSizes(client)
+		if err != nil {
+			logWriter.Write([]byte("Failed to list sizes: " + err.Error() + "\n"))
+			return nil, err
+		}
+	}
+
+	// The region, size and image are only used when the droplet is recreated or resized
+	change := util.DropletUnchanged
+	if recreate {
+		change = util.DropletCreated
+	} else if resize {
+		change = util.DropletResized
+	}
+
+	err = util.ValidateTargetOptions(client, targetOptions, sizes, change)
+	if err != nil {
+		logWriter.Write([]byte("Invalid target config options: " + err.Error() + "\n"))
+		return nil, err
+	}
+
+	if recreate || resize || growVolume {
+		costEstimate, err := util.EstimateCost(sizes, targetOptions)
+		if err != nil {
+			logWriter.Write([]byte("Failed to estimate cost: " + err.Error() + "\n"))
+			return nil, err
+		}
+
+		err = p.checkBudget(targetOptions, costEstimate)
+		if err != nil {
+			logWriter.Write([]byte(err.Error() + "\n"))
+			return nil, err
+		}
+	}
+
+	volumeResized := false
+	if volume != nil && volume.SizeGigaBytes != int64(targetOptions.DiskSize) {
+		logWriter.Write([]byte(fmt.Sprintf("Resizing volume from %d GB to %d GB...\n", volume.SizeGigaBytes, targetOptions.DiskSize)))
+
+		err = util.ResizeVolume(client, volume, targetOptions.DiskSize)
+		if err != nil {
+			logWriter.Write([]byte("Failed to resize volume: " + err.Error() + "\n"))
+			return nil, err
+		}
+
+		volumeResized = true
+		logWriter.Write([]byte("Volume resized.\n"))
+	}
+
+	droplet, err := p.createDroplet(client, targetReq.Target, targetOptions, sizes, logWriter)
 	if err != nil {
 		logWriter.Write([]byte("Failed to create droplet: " + err.Error() + "\n"))
 		return nil, err
 	}
 
+	if targetOptions.MaxLifetime != nil && *targetOptions.MaxLifetime != "" {
+		p.startReaper(targetOptions)
+	}
+
+	if droplet.Status == "new" {
+		droplet, err = util.WaitForDropletStatus(client, droplet.ID, "active", 5*time.Minute)
+		if err != nil {
+			logWriter.Write([]byte("Failed to wait for droplet: " + err.Error() + "\n"))
+			return nil, err
+		}
+	}
+
+	err = p.assignReservedIp(client, targetReq.Target, targetOptions, droplet, logWriter)
+	if err != nil {
+		logWriter.Write([]byte("Failed to assign reserved IP: " + err.Error() + "\n"))
+		return nil, err
+	}
+
+	if util.GetDropletSize(droplet) != targetOptions.Size {
+		err = p.resizeDroplet(client, droplet, targetOptions.Size, logWriter)
+		if err != nil {
+			logWriter.Write([]byte("Failed to resize droplet: " + err.Error() + "\n"))
+			return nil, err
+		}
+	}
+
+	// The droplet might have been powered off by the stop strategy, a resize, the idle watcher or from the DigitalOcean console
+	if droplet.Status == "off" {
+		logWriter.Write([]byte("Powering on droplet...\n"))
+
+		err = util.PowerOnDroplet(client, droplet)
+		if err != nil {
+			logWriter.Write([]byte("Failed to power on droplet: " + err.Error() + "\n"))
+			return nil, err
+		}
+
+		logWriter.Write([]byte("Droplet powered on.\n"))
+	}
+
+	agentSpinner := log_writers.ShowSpinner(logWriter, "Waiting for the agent to start", "Agent started")
 	err = p.waitForDial(targetReq.Target.Id, 10*time.Minute)
+	close(agentSpinner)
 	if err != nil {
 		logWriter.Write([]byte("Failed to dial: " + err.Error() + "\n"))
 		return nil, err
 	}
 
+	// Droplets created after the resize grow the filesystem from their user data
+	if volumeResized {
+		err = p.resizeFilesystem(targetReq.Target)
+		if err != nil {
+			logWriter.Write([]byte("Failed to resize filesystem: " + err.Error() + "\n"))
+			return nil, err
+		}
+	}
+
 	return new(provider_util.Empty), nil
 }
 
+func (p *DigitalOceanProvider) resizeFilesystem(target *models.Target) error {
+	sshClient, err := p.getSshClient(target.Id)
+	if err != nil {
+		return err
+	}
+	defer sshClient.Close()
+
+	session, err := sshClient.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	output, err := session.CombinedOutput("sudo resize2fs " + util.GetVolumeDevicePath(util.GetDropletName(target)))
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, string(output))
+	}
+
+	return nil
+}
+
+// Leaves the droplet powered off
+func (p *DigitalOceanProvider) resizeDroplet(client *godo.Client, droplet *godo.Droplet, size string, logWriter io.Writer) error {
+	logWriter.Write([]byte(fmt.Sprintf("Resizing droplet from %s to %s...\n", util.GetDropletSize(droplet), size)))
+
+	if droplet.Status != "off" {
+		logWriter.Write([]byte("Powering off droplet...\n"))
+
+		err := util.PowerOffDroplet(client, droplet)
+		if err != nil {
+			return err
+		}
+
+		droplet.Status = "off"
+		logWriter.Write([]byte("Droplet powered off.\n"))
+	}
+
+	err := util.ResizeDroplet(client, droplet, size)
+	if err != nil {
+		return err
+	}
+
+	logWriter.Write([]byte("Droplet resized.\n"))
+
+	return nil
+}
+
 func (p *DigitalOceanProvider) StartWorkspace(workspaceReq *provider.WorkspaceRequest) (*provider_util.Empty, error) {
 	if p.DaytonaDownloadUrl == nil {
 		return nil, errors.New("DaytonaDownloadUrl not set. Did you forget to call Initialize")
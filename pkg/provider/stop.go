@@ -24,6 +24,24 @@ func (p *DigitalOceanProvider) StopTarget(targetReq *provider.TargetRequest) (*p
 		return nil, err
 	}
 
+	if targetOptions.StopStrategy == types.StopStrategyPowerOff {
+		droplet, err := util.GetDroplet(client, util.GetDropletName(targetReq.Target))
+		if err != nil {
+			logWriter.Write([]byte("Failed to get droplet: " + err.Error() + "\n"))
+			return nil, err
+		}
+
+		err = util.PowerOffDroplet(client, droplet)
+		if err != nil {
+			logWriter.Write([]byte("Failed to power off droplet: " + err.Error() + "\n"))
+			return nil, err
+		}
+
+		logWriter.Write([]byte("Droplet powered off.\n"))
+
+		return new(provider_util.Empty), nil
+	}
+
 	err = util.DeleteDroplet(client, targetReq.Target, false)
 	if err != nil {
 		logWriter.Write([]byte("Failed to delete droplet: " + err.Error() + "\n"))
@@ -32,6 +50,16 @@ func (p *DigitalOceanProvider) StopTarget(targetReq *provider.TargetRequest) (*p
 
 	logWriter.Write([]byte("Droplet deleted.\n"))
 
+	if targetOptions.StopStrategy == types.StopStrategySnapshotVolume {
+		err = util.SnapshotVolume(client, util.GetDropletName(targetReq.Target))
+		if err != nil {
+			logWriter.Write([]byte("Failed to snapshot volume: " + err.Error() + "\n"))
+			return nil, err
+		}
+
+		logWriter.Write([]byte("Volume snapshotted and deleted.\n"))
+	}
+
 	return new(provider_util.Empty), nil
 }
 
@@ -0,0 +1,93 @@
+package util
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/digitalocean/godo"
+)
+
+func CountDroplets(client *godo.Client) (int, error) {
+	droplets, resp, err := client.Droplets.List(context.Background(), &godo.ListOptions{PerPage: 1})
+	if err != nil {
+		return 0, fmt.Errorf("error listing droplets: %v", err)
+	}
+
+	if resp.Meta != nil {
+		return resp.Meta.Total, nil
+	}
+
+	return len(droplets), nil
+}
+
+func CountVolumes(client *godo.Client) (int, error) {
+	volumes, resp, err := client.Storage.ListVolumes(context.Background(), &godo.ListVolumeParams{ListOptions: &godo.ListOptions{PerPage: 1}})
+	if err != nil {
+		return 0, fmt.Errorf("error listing volumes: %v", err)
+	}
+
+	if resp.Meta != nil {
+		return resp.Meta.Total, nil
+	}
+
+	return len(volumes), nil
+}
+
+// Returns the total size of the volumes in the region
+func GetVolumeStorage(client *godo.Client, region string) (int64, error) {
+	volumes, err := listAll(func(ctx context.Context, opts *godo.ListOptions) ([]godo.Volume, *godo.Response, error) {
+		return client.Storage.ListVolumes(ctx, &godo.ListVolumeParams{Region: region, ListOptions: opts})
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error listing volumes: %v", err)
+	}
+
+	var total int64
+	for _, volume := range volumes {
+		total += volume.SizeGigaBytes
+	}
+
+	return total, nil
+}
+
+// Returns an error if creating a droplet, and optionally a volume of the given size, would exceed the account's limits
+// The volume storage limit is not exposed by the DigitalOcean API so it has to be passed in
+func CheckAccountLimits(client *godo.Client, region string, volumeSizeGigaBytes int, volumeStorageLimitGigaBytes int) error {
+	account, _, err := client.Account.Get(context.Background())
+	if err != nil {
+		return fmt.Errorf("error getting account: %v", err)
+	}
+
+	dropletCount, err := CountDroplets(client)
+	if err != nil {
+		return err
+	}
+
+	if dropletCount >= account.DropletLimit {
+		return fmt.Errorf("droplet limit reached: the account is using %d of %d droplets", dropletCount, account.DropletLimit)
+	}
+
+	if volumeSizeGigaBytes == 0 {
+		return nil
+	}
+
+	volumeCount, err := CountVolumes(client)
+	if err != nil {
+		return err
+	}
+
+	if volumeCount >= account.VolumeLimit {
+		return fmt.Errorf("volume limit reached: the account is using %d of %d volumes", volumeCount, account.VolumeLimit)
+	}
+
+	volumeStorage, err := GetVolumeStorage(client, region)
+	if err != nil {
+		return err
+	}
+
+	if volumeStorage+int64(volumeSizeGigaBytes) > int64(volumeStorageLimitGigaBytes) {
+		return fmt.Errorf("volume storage limit exceeded: a %d GB volume does not fit next to the %d GB already used of %d GB in region %s", volumeSizeGigaBytes, volumeStorage, volumeStorageLimitGigaBytes, region)
+	}
+
+	return nil
+}
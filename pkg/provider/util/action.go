@@ -0,0 +1,27 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+func WaitForAction(client *godo.Client, action *godo.Action) error {
+	for action.Status == godo.ActionInProgress {
+		time.Sleep(2 * time.Second)
+
+		var err error
+		action, _, err = client.Actions.Get(context.Background(), action.ID)
+		if err != nil {
+			return fmt.Errorf("error getting action: %v", err)
+		}
+	}
+
+	if action.Status != godo.ActionCompleted {
+		return fmt.Errorf("%s action %d finished with status %s", action.Type, action.ID, action.Status)
+	}
+
+	return nil
+}
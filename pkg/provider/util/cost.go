@@ -0,0 +1,44 @@
+package util
+
+import (
+	"github.com/daytonaio/daytona-provider-digitalocean/pkg/types"
+	"github.com/digitalocean/godo"
+)
+
+const (
+	// https://www.digitalocean.com/pricing/volumes
+	volumePricePerGbMonthly = 0.10
+	// DigitalOcean bills hourly up to 672 hours per month
+	billableHoursPerMonth = 672
+	// https://www.digitalocean.com/pricing/backups
+	weeklyBackupsPriceRate = 0.20
+)
+
+func EstimateCost(sizes []godo.Size, targetOptions *types.TargetOptions) (*types.CostEstimate, error) {
+	size, err := FindSize(sizes, targetOptions.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetCostEstimate(size, targetOptions.DiskSize, targetOptions.Backups), nil
+}
+
+func GetCostEstimate(size *godo.Size, diskSize int, backups bool) *types.CostEstimate {
+	estimate := &types.CostEstimate{
+		Size:           size.Slug,
+		DropletHourly:  size.PriceHourly,
+		DropletMonthly: size.PriceMonthly,
+		DiskSize:       diskSize,
+		VolumeMonthly:  float64(diskSize) * volumePricePerGbMonthly,
+		VolumeHourly:   float64(diskSize) * volumePricePerGbMonthly / billableHoursPerMonth,
+	}
+
+	if backups {
+		estimate.BackupsMonthly = size.PriceMonthly * weeklyBackupsPriceRate
+	}
+
+	estimate.TotalMonthly = estimate.DropletMonthly + estimate.VolumeMonthly + estimate.BackupsMonthly
+	estimate.TotalHourly = estimate.DropletHourly + estimate.VolumeHourly + estimate.BackupsMonthly/billableHoursPerMonth
+
+	return estimate
+}
@@ -0,0 +1,84 @@
+package util
+
+import (
+	"math"
+	"testing"
+
+	"github.com/daytonaio/daytona-provider-digitalocean/pkg/types"
+	"github.com/digitalocean/godo"
+)
+
+func TestGetCostEstimate(t *testing.T) {
+	size := &godo.Size{Slug: "s-2vcpu-4gb", PriceMonthly: 24, PriceHourly: 0.03571}
+
+	tests := []struct {
+		name             string
+		diskSize         int
+		backups          bool
+		wantVolume       float64
+		wantBackups      float64
+		wantTotalMonthly float64
+		wantTotalHourly  float64
+	}{
+		{
+			name:             "without backups",
+			diskSize:         20,
+			wantVolume:       2,
+			wantTotalMonthly: 26,
+			wantTotalHourly:  0.03571 + 2.0/672,
+		},
+		{
+			name:             "with backups",
+			diskSize:         100,
+			backups:          true,
+			wantVolume:       10,
+			wantBackups:      4.8,
+			wantTotalMonthly: 38.8,
+			wantTotalHourly:  0.03571 + 10.0/672 + 4.8/672,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GetCostEstimate(size, tt.diskSize, tt.backups)
+
+			if got.Size != size.Slug || got.DiskSize != tt.diskSize || got.DropletMonthly != size.PriceMonthly || got.DropletHourly != size.PriceHourly {
+				t.Errorf("GetCostEstimate() = %+v, does not match size %s and disk size %d", got, size.Slug, tt.diskSize)
+			}
+
+			for _, c := range []struct {
+				field     string
+				got, want float64
+			}{
+				{"VolumeMonthly", got.VolumeMonthly, tt.wantVolume},
+				{"BackupsMonthly", got.BackupsMonthly, tt.wantBackups},
+				{"TotalMonthly", got.TotalMonthly, tt.wantTotalMonthly},
+				{"TotalHourly", got.TotalHourly, tt.wantTotalHourly},
+			} {
+				if math.Abs(c.got-c.want) > 1e-9 {
+					t.Errorf("GetCostEstimate().%s = %f, want %f", c.field, c.got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestEstimateCost(t *testing.T) {
+	sizes := []godo.Size{
+		{Slug: "s-1vcpu-1gb", PriceMonthly: 6},
+		{Slug: "s-2vcpu-4gb", PriceMonthly: 24},
+	}
+
+	estimate, err := EstimateCost(sizes, &types.TargetOptions{Size: "s-2vcpu-4gb", DiskSize: 20})
+	if err != nil {
+		t.Fatalf("EstimateCost() error = %v", err)
+	}
+	if estimate.DropletMonthly != 24 {
+		t.Errorf("EstimateCost() droplet monthly = %f, want 24", estimate.DropletMonthly)
+	}
+
+	_, err = EstimateCost(sizes, &types.TargetOptions{Size: "s-8vcpu-16gb", DiskSize: 20})
+	if err == nil {
+		t.Errorf("EstimateCost() with unknown size returned no error")
+	}
+}
@@ -2,6 +2,7 @@ package util
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"time"
 
@@ -15,10 +16,22 @@ func DeleteDroplet(client *godo.Client, target *models.Target, deleteVolume bool
 		if err != nil {
 			return err
 		}
+
+		err = DeleteFirewall(client, GetDropletName(target))
+		if err != nil {
+			return err
+		}
+
+		err = DeleteVolumeSnapshot(client, GetDropletName(target))
+		if err != nil {
+			return err
+		}
 	}
 
 	droplet, err := GetDroplet(client, GetDropletName(target))
-	if err != nil {
+	if errors.Is(err, ErrDropletNotFound) {
+		return nil
+	} else if err != nil {
 		return err
 	}
 
@@ -0,0 +1,37 @@
+package util
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const expiryTagPrefix = "daytona-expires-"
+
+func GetExpiryTag(expiresAt time.Time) string {
+	return expiryTagPrefix + strconv.FormatInt(expiresAt.Unix(), 10)
+}
+
+// Returns the expiry stamped on a resource, false if the resource does not expire
+func GetExpiry(tags []string) (time.Time, bool) {
+	for _, tag := range tags {
+		timestamp, found := strings.CutPrefix(tag, expiryTagPrefix)
+		if !found {
+			continue
+		}
+
+		unix, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		return time.Unix(unix, 0), true
+	}
+
+	return time.Time{}, false
+}
+
+// Returns the ID of the target a droplet, volume or volume snapshot was created for
+func GetTargetIdFromName(name string) (string, bool) {
+	return strings.CutPrefix(name, "daytona-")
+}
@@ -0,0 +1,74 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetExpiry(t *testing.T) {
+	tests := []struct {
+		name        string
+		tags        []string
+		want        time.Time
+		wantExpires bool
+	}{
+		{
+			name: "no tags",
+			tags: nil,
+		},
+		{
+			name: "no expiry tag",
+			tags: []string{"daytona", "daytona-target:123"},
+		},
+		{
+			name:        "expiry tag",
+			tags:        []string{"daytona", "daytona-expires-1700000000", "team:backend"},
+			want:        time.Unix(1700000000, 0),
+			wantExpires: true,
+		},
+		{
+			name:        "generated tag",
+			tags:        []string{GetExpiryTag(time.Unix(1800000000, 0))},
+			want:        time.Unix(1800000000, 0),
+			wantExpires: true,
+		},
+		{
+			name:        "invalid timestamp is skipped",
+			tags:        []string{"daytona-expires-soon", "daytona-expires-1700000000"},
+			want:        time.Unix(1700000000, 0),
+			wantExpires: true,
+		},
+		{
+			name: "only invalid timestamps",
+			tags: []string{"daytona-expires-", "daytona-expires-tomorrow"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, expires := GetExpiry(tt.tags)
+			if expires != tt.wantExpires || !got.Equal(tt.want) {
+				t.Errorf("GetExpiry(%v) = %v, %v, want %v, %v", tt.tags, got, expires, tt.want, tt.wantExpires)
+			}
+		})
+	}
+}
+
+func TestGetTargetIdFromName(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   string
+		wantOk bool
+	}{
+		{"daytona-123", "123", true},
+		{"daytona-builder-1a2b3c4d", "builder-1a2b3c4d", true},
+		{"my-droplet", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := GetTargetIdFromName(tt.name)
+		if ok != tt.wantOk || (ok && got != tt.want) {
+			t.Errorf("GetTargetIdFromName(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
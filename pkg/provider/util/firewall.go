@@ -0,0 +1,160 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/digitalocean/godo"
+)
+
+var allAddresses = []string{"0.0.0.0/0", "::/0"}
+
+// Parses inbound rules in the form of <protocol>:<ports>[:<source>[;<source>...]], separated by commas
+// E.g. "tcp:22:203.0.113.0/24,udp:41641"
+func ParseFirewallInboundRules(rules string) ([]godo.InboundRule, error) {
+	inboundRules := []godo.InboundRule{}
+
+	for _, rule := range strings.Split(rules, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+
+		parts := strings.SplitN(rule, ":", 3)
+		protocol := strings.ToLower(parts[0])
+
+		switch protocol {
+		case "tcp", "udp":
+			if len(parts) < 2 || parts[1] == "" {
+				return nil, fmt.Errorf("invalid firewall rule %s: ports are required for %s", rule, protocol)
+			}
+		case "icmp":
+		default:
+			return nil, fmt.Errorf("invalid firewall rule %s: unsupported protocol %s", rule, protocol)
+		}
+
+		inboundRule := godo.InboundRule{
+			Protocol: protocol,
+			Sources:  &godo.Sources{Addresses: allAddresses},
+		}
+
+		if protocol != "icmp" {
+			inboundRule.PortRange = parts[1]
+		}
+
+		if len(parts) == 3 && parts[2] != "" {
+			inboundRule.Sources = &godo.Sources{Addresses: strings.Split(parts[2], ";")}
+		}
+
+		inboundRules = append(inboundRules, inboundRule)
+	}
+
+	return inboundRules, nil
+}
+
+// Creates a firewall applied to all droplets with the given tag or updates the rules of an existing one
+// Public inbound traffic is denied unless allowed by the inbound rules, outbound traffic is not restricted
+func CreateOrUpdateFirewall(client *godo.Client, name string, inboundRules []godo.InboundRule) (*godo.Firewall, error) {
+	_, _, err := client.Tags.Create(context.Background(), &godo.TagCreateRequest{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("error creating tag: %v", err)
+	}
+
+	firewallRequest := &godo.FirewallRequest{
+		Name:         name,
+		InboundRules: inboundRules,
+		OutboundRules: []godo.OutboundRule{
+			{Protocol: "tcp", PortRange: "all", Destinations: &godo.Destinations{Addresses: allAddresses}},
+			{Protocol: "udp", PortRange: "all", Destinations: &godo.Destinations{Addresses: allAddresses}},
+			{Protocol: "icmp", Destinations: &godo.Destinations{Addresses: allAddresses}},
+		},
+		// Firewall tags select the droplets the firewall applies to, so the target's resource tags can not be used here
+		Tags: []string{name},
+	}
+
+	firewall, err := GetFirewallByName(client, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if firewall != nil {
+		firewall, _, err = client.Firewalls.Update(context.Background(), firewall.ID, firewallRequest)
+		if err != nil {
+			return nil, fmt.Errorf("error updating firewall: %v", err)
+		}
+		return firewall, nil
+	}
+
+	firewall, _, err = client.Firewalls.Create(context.Background(), firewallRequest)
+	if err != nil {
+		return nil, fmt.Errorf("error creating firewall: %v", err)
+	}
+
+	return firewall, nil
+}
+
+// Returns whether any of the inbound rules allows traffic to the port from some source
+func AllowsPort(inboundRules []godo.InboundRule, protocol string, port int) bool {
+	for _, rule := range inboundRules {
+		if rule.Protocol != protocol {
+			continue
+		}
+
+		if rule.PortRange == "all" || rule.PortRange == "0" {
+			return true
+		}
+
+		from, to, isRange := strings.Cut(rule.PortRange, "-")
+		if !isRange {
+			to = from
+		}
+
+		fromPort, fromErr := strconv.Atoi(from)
+		toPort, toErr := strconv.Atoi(to)
+		if fromErr == nil && toErr == nil && fromPort <= port && port <= toPort {
+			return true
+		}
+	}
+
+	return false
+}
+
+func ListFirewalls(client *godo.Client) ([]godo.Firewall, error) {
+	firewalls, err := listAll(client.Firewalls.List)
+	if err != nil {
+		return nil, fmt.Errorf("error listing firewalls: %v", err)
+	}
+
+	return firewalls, nil
+}
+
+func GetFirewallByName(client *godo.Client, name string) (*godo.Firewall, error) {
+	firewalls, err := ListFirewalls(client)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, firewall := range firewalls {
+		if firewall.Name == name {
+			return &firewall, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func DeleteFirewall(client *godo.Client, name string) error {
+	firewall, err := GetFirewallByName(client, name)
+	if err != nil {
+		return err
+	}
+
+	if firewall == nil {
+		return nil
+	}
+
+	_, err = client.Firewalls.Delete(context.Background(), firewall.ID)
+	return err
+}
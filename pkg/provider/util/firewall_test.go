@@ -0,0 +1,111 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/digitalocean/godo"
+)
+
+func TestParseFirewallInboundRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   string
+		want    []godo.InboundRule
+		wantErr bool
+	}{
+		{
+			name:  "empty",
+			rules: "",
+			want:  []godo.InboundRule{},
+		},
+		{
+			name:  "tcp port from anywhere",
+			rules: "tcp:22",
+			want: []godo.InboundRule{
+				{Protocol: "tcp", PortRange: "22", Sources: &godo.Sources{Addresses: allAddresses}},
+			},
+		},
+		{
+			name:  "sources and port range",
+			rules: "TCP:8000-8080:203.0.113.0/24;2001:db8::/32",
+			want: []godo.InboundRule{
+				{Protocol: "tcp", PortRange: "8000-8080", Sources: &godo.Sources{Addresses: []string{"203.0.113.0/24", "2001:db8::/32"}}},
+			},
+		},
+		{
+			name:  "multiple rules",
+			rules: " tcp:22:203.0.113.0/24 , udp:41641,icmp,",
+			want: []godo.InboundRule{
+				{Protocol: "tcp", PortRange: "22", Sources: &godo.Sources{Addresses: []string{"203.0.113.0/24"}}},
+				{Protocol: "udp", PortRange: "41641", Sources: &godo.Sources{Addresses: allAddresses}},
+				{Protocol: "icmp", Sources: &godo.Sources{Addresses: allAddresses}},
+			},
+		},
+		{
+			name:  "icmp ignores ports",
+			rules: "icmp:all",
+			want: []godo.InboundRule{
+				{Protocol: "icmp", Sources: &godo.Sources{Addresses: allAddresses}},
+			},
+		},
+		{
+			name:    "missing ports",
+			rules:   "tcp",
+			wantErr: true,
+		},
+		{
+			name:    "empty ports",
+			rules:   "udp::203.0.113.0/24",
+			wantErr: true,
+		},
+		{
+			name:    "unsupported protocol",
+			rules:   "sctp:22",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFirewallInboundRules(tt.rules)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFirewallInboundRules(%q) error = %v, wantErr %v", tt.rules, err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFirewallInboundRules(%q) = %+v, want %+v", tt.rules, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllowsPort(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+		want  bool
+	}{
+		{name: "no rules", rules: "", want: false},
+		{name: "exact port", rules: "tcp:22:203.0.113.0/24", want: true},
+		{name: "port range", rules: "tcp:20-30", want: true},
+		{name: "all ports", rules: "tcp:all", want: true},
+		{name: "other port", rules: "tcp:2222", want: false},
+		{name: "other range", rules: "tcp:1000-2000", want: false},
+		{name: "other protocol", rules: "udp:22,icmp", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inboundRules, err := ParseFirewallInboundRules(tt.rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := AllowsPort(inboundRules, "tcp", 22)
+			if got != tt.want {
+				t.Errorf("AllowsPort(%q, tcp, 22) = %v, want %v", tt.rules, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/digitalocean/godo"
+)
+
+// Resolves an image slug, numeric image ID or the name of a snapshot or custom image
+func GetDropletCreateImage(client *godo.Client, image string, region string) (godo.DropletCreateImage, error) {
+	if id, err := strconv.Atoi(image); err == nil {
+		img, _, err := client.Images.GetByID(context.Background(), id)
+		if err != nil {
+			return godo.DropletCreateImage{}, fmt.Errorf("error getting image %d: %v", id, err)
+		}
+
+		return godo.DropletCreateImage{ID: img.ID}, checkImageRegion(img, region)
+	}
+
+	img, resp, err := client.Images.GetBySlug(context.Background(), image)
+	if err == nil {
+		return godo.DropletCreateImage{Slug: img.Slug}, checkImageRegion(img, region)
+	} else if resp == nil || resp.StatusCode != http.StatusNotFound {
+		return godo.DropletCreateImage{}, fmt.Errorf("error getting image %s: %v", image, err)
+	}
+
+	userImages, err := listImages(client.Images.ListUser)
+	if err != nil {
+		return godo.DropletCreateImage{}, err
+	}
+
+	found := findImageByName(userImages, image)
+	if found != nil {
+		return godo.DropletCreateImage{ID: found.ID}, checkImageRegion(found, region)
+	}
+
+	distributionImages, err := listImages(client.Images.ListDistribution)
+	if err != nil {
+		return godo.DropletCreateImage{}, err
+	}
+
+	matches := getCloseImageMatches(image, append(userImages, distributionImages...))
+	if len(matches) == 0 {
+		return godo.DropletCreateImage{}, fmt.Errorf("image %s not found", image)
+	}
+
+	return godo.DropletCreateImage{}, fmt.Errorf("image %s not found, did you mean one of: %s", image, strings.Join(matches, ", "))
+}
+
+// Returns the snapshot or custom image with the given name or nil if it does not exist
+func GetUserImageByName(client *godo.Client, name string) (*godo.Image, error) {
+	userImages, err := ListUserImages(client)
+	if err != nil {
+		return nil, err
+	}
+
+	return findImageByName(userImages, name), nil
+}
+
+func ListUserImages(client *godo.Client) ([]godo.Image, error) {
+	return listImages(client.Images.ListUser)
+}
+
+func GetBakedImageName(daytonaVersion string, image string) string {
+	return fmt.Sprintf("daytona-%s-%s", daytonaVersion, image)
+}
+
+// Returns the Daytona version of an image baked from the base image, false if the name is not one of a baked image.
+// Versions with a dash, e.g. development builds, are not recognized as the boundary to the image would be ambiguous.
+func GetBakedImageVersion(name string, image string) (string, bool) {
+	version, found := strings.CutPrefix(name, "daytona-")
+	if !found {
+		return "", false
+	}
+
+	version, found = strings.CutSuffix(version, "-"+image)
+	if !found || version == "" || strings.Contains(version, "-") {
+		return "", false
+	}
+
+	return version, true
+}
+
+func IsImageInRegion(image *godo.Image, region string) bool {
+	return len(image.Regions) == 0 || slices.Contains(image.Regions, region)
+}
+
+func findImageByName(images []godo.Image, name string) *godo.Image {
+	var found *godo.Image
+	for _, image := range images {
+		if image.Name != name {
+			continue
+		}
+
+		// Prefer the most recent image if multiple images share the same name
+		if found == nil || image.Created > found.Created {
+			found = &image
+		}
+	}
+
+	return found
+}
+
+// An empty region is not checked, e.g. because the region itself is invalid
+func checkImageRegion(image *godo.Image, region string) error {
+	if region != "" && !IsImageInRegion(image, region) {
+		return fmt.Errorf("image %s is not available in region %s, available regions: %s", getImageIdentifier(image), region, strings.Join(image.Regions, ", "))
+	}
+
+	return nil
+}
+
+func listImages(list func(context.Context, *godo.ListOptions) ([]godo.Image, *godo.Response, error)) ([]godo.Image, error) {
+	images, err := listAll(list)
+	if err != nil {
+		return nil, fmt.Errorf("error listing images: %v", err)
+	}
+
+	return images, nil
+}
+
+func getImageIdentifier(image *godo.Image) string {
+	if image.Slug != "" {
+		return image.Slug
+	}
+
+	return image.Name
+}
+
+func getCloseImageMatches(image string, images []godo.Image) []string {
+	type match struct {
+		identifier string
+		distance   int
+	}
+
+	query := strings.ToLower(image)
+	maxDistance := max(len(query)/3, 2)
+
+	matches := []match{}
+	for _, img := range images {
+		identifier := getImageIdentifier(&img)
+		if identifier == "" || slices.ContainsFunc(matches, func(m match) bool { return m.identifier == identifier }) {
+			continue
+		}
+
+		candidate := strings.ToLower(identifier)
+		distance := levenshtein(query, candidate)
+		if strings.Contains(candidate, query) || strings.Contains(query, candidate) {
+			distance = 0
+		}
+
+		if distance <= maxDistance {
+			matches = append(matches, match{identifier: identifier, distance: distance})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].distance < matches[j].distance })
+
+	result := []string{}
+	for i := 0; i < len(matches) && i < 5; i++ {
+		result = append(result, matches[i].identifier)
+	}
+
+	return result
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr := make([]int, len(b)+1)
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,107 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/digitalocean/godo"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"ubuntu", "ubuntu", 0},
+		{"ubuntu", "ubunty", 1},
+		{"ubuntu-22-04-x64", "ubuntu-22-10-x64", 2},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tt := range tests {
+		got := levenshtein(tt.a, tt.b)
+		if got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestGetCloseImageMatches(t *testing.T) {
+	images := []godo.Image{
+		{Slug: "ubuntu-22-04-x64"},
+		{Slug: "ubuntu-24-04-x64"},
+		{Slug: "debian-12-x64"},
+		{Slug: "docker-20-04"},
+		{Name: "my-snapshot"},
+		// Duplicates are only suggested once
+		{Slug: "debian-12-x64"},
+	}
+
+	tests := []struct {
+		name  string
+		image string
+		want  []string
+	}{
+		{
+			name:  "typo",
+			image: "ubuntu-22-04-x46",
+			want:  []string{"ubuntu-22-04-x64", "ubuntu-24-04-x64"},
+		},
+		{
+			name:  "substring",
+			image: "debian",
+			want:  []string{"debian-12-x64"},
+		},
+		{
+			name:  "case insensitive",
+			image: "Docker-20-04",
+			want:  []string{"docker-20-04"},
+		},
+		{
+			name:  "snapshot name",
+			image: "my-snapshots",
+			want:  []string{"my-snapshot"},
+		},
+		{
+			name:  "no match",
+			image: "fedora-39-x64",
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getCloseImageMatches(tt.image, images)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getCloseImageMatches(%q) = %v, want %v", tt.image, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetBakedImageVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		image       string
+		wantVersion string
+		wantBaked   bool
+	}{
+		{name: GetBakedImageName("v0.52.0", "docker-20-04"), image: "docker-20-04", wantVersion: "v0.52.0", wantBaked: true},
+		{name: "daytona-v0.51.1-ubuntu-22-04-x64", image: "ubuntu-22-04-x64", wantVersion: "v0.51.1", wantBaked: true},
+		{name: "daytona-v0.52.0-ubuntu-22-04-x64", image: "docker-20-04", wantBaked: false},
+		{name: "daytona-v0.52.0-ubuntu-22-04-x64", image: "22-04-x64", wantBaked: false},
+		{name: "daytona-0.0.0-dev-docker-20-04", image: "docker-20-04", wantBaked: false},
+		{name: "daytona--docker-20-04", image: "docker-20-04", wantBaked: false},
+		{name: "my-snapshot", image: "docker-20-04", wantBaked: false},
+	}
+
+	for _, tt := range tests {
+		version, baked := GetBakedImageVersion(tt.name, tt.image)
+		if baked != tt.wantBaked || version != tt.wantVersion {
+			t.Errorf("GetBakedImageVersion(%q, %q) = %q, %v, want %q, %v", tt.name, tt.image, version, baked, tt.wantVersion, tt.wantBaked)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package util
+
+import (
+	"context"
+
+	"github.com/digitalocean/godo"
+)
+
+// Fetches every page of a godo list call
+func listAll[T any](list func(context.Context, *godo.ListOptions) ([]T, *godo.Response, error)) ([]T, error) {
+	items := []T{}
+	opts := &godo.ListOptions{Page: 1, PerPage: 200}
+
+	for {
+		page, resp, err := list(context.Background(), opts)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, page...)
+
+		if resp.Links == nil || resp.Links.IsLastPage() {
+			break
+		}
+		opts.Page++
+	}
+
+	return items, nil
+}
@@ -0,0 +1,61 @@
+package util
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/digitalocean/godo"
+)
+
+// Gracefully shuts down the droplet and forces a power off if the shutdown fails
+func PowerOffDroplet(client *godo.Client, droplet *godo.Droplet) error {
+	if droplet.Status == "off" {
+		return nil
+	}
+
+	action, _, err := client.DropletActions.Shutdown(context.Background(), droplet.ID)
+	if err == nil {
+		err = WaitForAction(client, action)
+		if err == nil {
+			return nil
+		}
+	}
+
+	action, _, err = client.DropletActions.PowerOff(context.Background(), droplet.ID)
+	if err != nil {
+		return fmt.Errorf("error powering off droplet: %v", err)
+	}
+
+	return WaitForAction(client, action)
+}
+
+func PowerOnDroplet(client *godo.Client, droplet *godo.Droplet) error {
+	if droplet.Status == "active" {
+		return nil
+	}
+
+	action, _, err := client.DropletActions.PowerOn(context.Background(), droplet.ID)
+	if err != nil {
+		return fmt.Errorf("error powering on droplet: %v", err)
+	}
+
+	return WaitForAction(client, action)
+}
+
+// Resizes CPU and RAM of a powered off droplet, the disk is kept so the resize can be reverted
+func ResizeDroplet(client *godo.Client, droplet *godo.Droplet, size string) error {
+	action, _, err := client.DropletActions.Resize(context.Background(), droplet.ID, size, false)
+	if err != nil {
+		return fmt.Errorf("error resizing droplet: %v", err)
+	}
+
+	return WaitForAction(client, action)
+}
+
+func GetDropletSize(droplet *godo.Droplet) string {
+	if droplet.Size != nil && droplet.Size.Slug != "" {
+		return droplet.Size.Slug
+	}
+
+	return droplet.SizeSlug
+}
@@ -0,0 +1,48 @@
+package util
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/digitalocean/godo"
+	"github.com/google/uuid"
+)
+
+// Returns the project with the given UUID or name
+func GetProject(client *godo.Client, project string) (*godo.Project, error) {
+	if _, err := uuid.Parse(project); err == nil {
+		p, _, err := client.Projects.Get(context.Background(), project)
+		if err != nil {
+			return nil, fmt.Errorf("error getting project %s: %v", project, err)
+		}
+
+		return p, nil
+	}
+
+	projects, err := listAll(client.Projects.List)
+	if err != nil {
+		return nil, fmt.Errorf("error listing projects: %v", err)
+	}
+
+	for _, p := range projects {
+		if p.Name == project {
+			return &p, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no project found with name %s", project)
+}
+
+func AssignToProject(client *godo.Client, project *godo.Project, resources ...godo.ResourceWithURN) error {
+	urns := []interface{}{}
+	for _, resource := range resources {
+		urns = append(urns, resource.URN())
+	}
+
+	_, _, err := client.Projects.AssignResources(context.Background(), project.ID, urns...)
+	if err != nil {
+		return fmt.Errorf("error assigning resources to project %s: %v", project.Name, err)
+	}
+
+	return nil
+}
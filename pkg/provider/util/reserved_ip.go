@@ -0,0 +1,78 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/digitalocean/godo"
+)
+
+const reservedIpTagPrefix = "daytona-reserved-ip:"
+
+// Tags can not contain dots, so the address is stored with dashes
+func GetReservedIpTag(ip string) string {
+	return reservedIpTagPrefix + strings.ReplaceAll(ip, ".", "-")
+}
+
+// Returns the reserved IP recorded on a resource, empty if none was allocated for it
+func GetReservedIpFromTags(tags []string) string {
+	for _, tag := range tags {
+		ip, found := strings.CutPrefix(tag, reservedIpTagPrefix)
+		if found {
+			return strings.ReplaceAll(ip, "-", ".")
+		}
+	}
+
+	return ""
+}
+
+func CreateReservedIp(client *godo.Client, region string, project *godo.Project) (*godo.ReservedIP, error) {
+	createRequest := &godo.ReservedIPCreateRequest{Region: region}
+	if project != nil {
+		createRequest.ProjectID = project.ID
+	}
+
+	reservedIp, _, err := client.ReservedIPs.Create(context.Background(), createRequest)
+	if err != nil {
+		return nil, fmt.Errorf("error creating reserved ip: %v", err)
+	}
+
+	return reservedIp, nil
+}
+
+// Returns the reserved IP with the given address if it can be assigned to the droplet
+func GetReservedIp(client *godo.Client, ip string, droplet *godo.Droplet) (*godo.ReservedIP, error) {
+	reservedIp, _, err := client.ReservedIPs.Get(context.Background(), ip)
+	if err != nil {
+		return nil, fmt.Errorf("error getting reserved ip %s: %v", ip, err)
+	}
+
+	if reservedIp.Region != nil && droplet.Region != nil && reservedIp.Region.Slug != droplet.Region.Slug {
+		return nil, fmt.Errorf("reserved ip %s is in region %s, expected %s", ip, reservedIp.Region.Slug, droplet.Region.Slug)
+	}
+
+	if reservedIp.Droplet != nil && reservedIp.Droplet.Name != droplet.Name {
+		return nil, fmt.Errorf("reserved ip %s is already assigned to droplet %s", ip, reservedIp.Droplet.Name)
+	}
+
+	return reservedIp, nil
+}
+
+func AssignReservedIp(client *godo.Client, reservedIp *godo.ReservedIP, droplet *godo.Droplet) error {
+	action, _, err := client.ReservedIPActions.Assign(context.Background(), reservedIp.IP, droplet.ID)
+	if err != nil {
+		return fmt.Errorf("error assigning reserved ip %s: %v", reservedIp.IP, err)
+	}
+
+	return WaitForAction(client, action)
+}
+
+func DeleteReservedIp(client *godo.Client, ip string) error {
+	_, err := client.ReservedIPs.Delete(context.Background(), ip)
+	if err != nil {
+		return fmt.Errorf("error deleting reserved ip %s: %v", ip, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/digitalocean/godo"
+)
+
+// Droplets, volumes and volume snapshots carrying a tag
+type TaggedResources struct {
+	Droplets        []godo.Droplet
+	Volumes         []godo.Volume
+	VolumeSnapshots []godo.Snapshot
+	// Firewall tags select droplets instead of tagging the firewall, so firewalls are never listed by tag
+	Firewalls []godo.Firewall
+}
+
+func ListTaggedResources(client *godo.Client, tag string) (*TaggedResources, error) {
+	resources := &TaggedResources{}
+
+	droplets, err := listAll(func(ctx context.Context, opts *godo.ListOptions) ([]godo.Droplet, *godo.Response, error) {
+		return client.Droplets.ListByTag(ctx, tag, opts)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing droplets: %v", err)
+	}
+	resources.Droplets = droplets
+
+	// Volumes and snapshots can not be listed by tag
+	volumes, err := listAll(func(ctx context.Context, opts *godo.ListOptions) ([]godo.Volume, *godo.Response, error) {
+		return client.Storage.ListVolumes(ctx, &godo.ListVolumeParams{ListOptions: opts})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing volumes: %v", err)
+	}
+
+	for _, volume := range volumes {
+		if slices.Contains(volume.Tags, tag) {
+			resources.Volumes = append(resources.Volumes, volume)
+		}
+	}
+
+	snapshots, err := listAll(client.Snapshots.ListVolume)
+	if err != nil {
+		return nil, fmt.Errorf("error listing volume snapshots: %v", err)
+	}
+
+	for _, snapshot := range snapshots {
+		if slices.Contains(snapshot.Tags, tag) {
+			resources.VolumeSnapshots = append(resources.VolumeSnapshots, snapshot)
+		}
+	}
+
+	return resources, nil
+}
+
+// Returns the names of all droplets, volumes and volume snapshots in the account, regardless of their tags
+func ListResourceNames(client *godo.Client) (map[string]bool, error) {
+	names := map[string]bool{}
+
+	droplets, err := listAll(client.Droplets.List)
+	if err != nil {
+		return nil, fmt.Errorf("error listing droplets: %v", err)
+	}
+
+	for _, droplet := range droplets {
+		names[droplet.Name] = true
+	}
+
+	volumes, err := listAll(func(ctx context.Context, opts *godo.ListOptions) ([]godo.Volume, *godo.Response, error) {
+		return client.Storage.ListVolumes(ctx, &godo.ListVolumeParams{ListOptions: opts})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing volumes: %v", err)
+	}
+
+	for _, volume := range volumes {
+		names[volume.Name] = true
+	}
+
+	snapshots, err := listAll(client.Snapshots.ListVolume)
+	if err != nil {
+		return nil, fmt.Errorf("error listing volume snapshots: %v", err)
+	}
+
+	for _, snapshot := range snapshots {
+		names[snapshot.Name] = true
+	}
+
+	return names, nil
+}
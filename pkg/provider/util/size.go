@@ -0,0 +1,46 @@
+package util
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/digitalocean/godo"
+)
+
+func ListSizes(client *godo.Client) ([]godo.Size, error) {
+	sizes, err := listAll(client.Sizes.List)
+	if err != nil {
+		return nil, fmt.Errorf("error listing sizes: %v", err)
+	}
+
+	return sizes, nil
+}
+
+func FindSize(sizes []godo.Size, slug string) (*godo.Size, error) {
+	index := slices.IndexFunc(sizes, func(s godo.Size) bool { return s.Slug == slug })
+	if index == -1 {
+		return nil, fmt.Errorf("size %s does not exist", slug)
+	}
+
+	return &sizes[index], nil
+}
+
+// Returns the available size with the smallest disk in the region, the cheapest one if disks are equal
+func FindSmallestSize(sizes []godo.Size, region string) (*godo.Size, error) {
+	var smallest *godo.Size
+	for i, size := range sizes {
+		if !size.Available || !slices.Contains(size.Regions, region) {
+			continue
+		}
+
+		if smallest == nil || size.Disk < smallest.Disk || (size.Disk == smallest.Disk && size.PriceMonthly < smallest.PriceMonthly) {
+			smallest = &sizes[i]
+		}
+	}
+
+	if smallest == nil {
+		return nil, fmt.Errorf("no size available in region %s", region)
+	}
+
+	return smallest, nil
+}
@@ -0,0 +1,91 @@
+package util
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/digitalocean/godo"
+)
+
+func GetSshKeys(client *godo.Client, sshKeys string) ([]godo.DropletCreateSSHKey, error) {
+	result := []godo.DropletCreateSSHKey{}
+
+	var accountKeys []godo.Key
+	for _, key := range strings.Split(sshKeys, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+
+		if id, err := strconv.Atoi(key); err == nil {
+			result = append(result, godo.DropletCreateSSHKey{ID: id})
+			continue
+		}
+
+		if !isPublicKey(key) {
+			result = append(result, godo.DropletCreateSSHKey{Fingerprint: key})
+			continue
+		}
+
+		if accountKeys == nil {
+			var err error
+			accountKeys, err = listKeys(client)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		uploadedKey, err := getOrCreateKey(client, accountKeys, key)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, godo.DropletCreateSSHKey{ID: uploadedKey.ID})
+	}
+
+	return result, nil
+}
+
+func getOrCreateKey(client *godo.Client, accountKeys []godo.Key, publicKey string) (*godo.Key, error) {
+	for _, accountKey := range accountKeys {
+		if trimPublicKey(accountKey.PublicKey) == trimPublicKey(publicKey) {
+			return &accountKey, nil
+		}
+	}
+
+	key, _, err := client.Keys.Create(context.Background(), &godo.KeyCreateRequest{
+		Name:      fmt.Sprintf("daytona-%x", sha256.Sum256([]byte(trimPublicKey(publicKey))))[:16],
+		PublicKey: publicKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error uploading ssh key: %v", err)
+	}
+
+	return key, nil
+}
+
+func listKeys(client *godo.Client) ([]godo.Key, error) {
+	keys, err := listAll(client.Keys.List)
+	if err != nil {
+		return nil, fmt.Errorf("error listing ssh keys: %v", err)
+	}
+
+	return keys, nil
+}
+
+func isPublicKey(key string) bool {
+	return strings.HasPrefix(key, "ssh-") || strings.HasPrefix(key, "ecdsa-") || strings.HasPrefix(key, "sk-")
+}
+
+// Strips the comment from a public key so keys can be compared by type and data only
+func trimPublicKey(key string) string {
+	fields := strings.Fields(key)
+	if len(fields) < 2 {
+		return key
+	}
+
+	return fields[0] + " " + fields[1]
+}
@@ -0,0 +1,62 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/digitalocean/godo"
+)
+
+const maxTagLength = 255
+
+var invalidTagCharsRegex = regexp.MustCompile(`[^a-zA-Z0-9:\-_]`)
+
+// Returns a tag of the prefix and the value with characters that are not allowed in DigitalOcean tags replaced,
+// truncated to the maximum tag length
+func SanitizeTag(prefix string, value string) string {
+	tag := prefix + invalidTagCharsRegex.ReplaceAllString(value, "-")
+	if len(tag) > maxTagLength {
+		tag = tag[:maxTagLength]
+	}
+
+	return tag
+}
+
+// Parses a comma separated list of tags
+func ParseTags(tags string) ([]string, error) {
+	result := []string{}
+
+	for _, tag := range strings.Split(tags, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+
+		if len(tag) > maxTagLength || invalidTagCharsRegex.MatchString(tag) {
+			return nil, fmt.Errorf("invalid tag %s: tags may only contain letters, numbers, colons, dashes and underscores and be at most %d characters long", tag, maxTagLength)
+		}
+
+		result = append(result, tag)
+	}
+
+	return result, nil
+}
+
+// Applies the tags to resources that were not tagged on creation
+func TagResources(client *godo.Client, tags []string, resources ...godo.Resource) error {
+	for _, tag := range tags {
+		_, _, err := client.Tags.Create(context.Background(), &godo.TagCreateRequest{Name: tag})
+		if err != nil {
+			return fmt.Errorf("error creating tag %s: %v", tag, err)
+		}
+
+		_, err = client.Tags.TagResources(context.Background(), tag, &godo.TagResourcesRequest{Resources: resources})
+		if err != nil {
+			return fmt.Errorf("error tagging resources with %s: %v", tag, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package util
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeTag(t *testing.T) {
+	tests := []struct {
+		prefix string
+		value  string
+		want   string
+	}{
+		{"", "daytona", "daytona"},
+		{"daytona-server:", "localhost", "daytona-server:localhost"},
+		{"daytona-target-name:", "my project", "daytona-target-name:my-project"},
+		{"", "team/backend.api", "team-backend-api"},
+		{"", "snake_case", "snake_case"},
+		{"", strings.Repeat("a", 300), strings.Repeat("a", maxTagLength)},
+		// The prefix counts towards the maximum length
+		{"daytona-target-name:", strings.Repeat("a", maxTagLength), "daytona-target-name:" + strings.Repeat("a", maxTagLength-len("daytona-target-name:"))},
+	}
+
+	for _, tt := range tests {
+		got := SanitizeTag(tt.prefix, tt.value)
+		if got != tt.want {
+			t.Errorf("SanitizeTag(%q, %q) = %q, want %q", tt.prefix, tt.value, got, tt.want)
+		}
+		if len(got) > maxTagLength {
+			t.Errorf("SanitizeTag(%q, %q) is %d characters long, want at most %d", tt.prefix, tt.value, len(got), maxTagLength)
+		}
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		tags    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "empty",
+			tags: "",
+			want: []string{},
+		},
+		{
+			name: "single",
+			tags: "team:backend",
+			want: []string{"team:backend"},
+		},
+		{
+			name: "whitespace and empty entries",
+			tags: " env:dev , ,cost_center-42,",
+			want: []string{"env:dev", "cost_center-42"},
+		},
+		{
+			name:    "invalid characters",
+			tags:    "env:dev,team/backend",
+			wantErr: true,
+		},
+		{
+			name:    "too long",
+			tags:    strings.Repeat("a", maxTagLength+1),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTags(tt.tags)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTags(%q) error = %v, wantErr %v", tt.tags, err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTags(%q) = %v, want %v", tt.tags, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	fileTokenPrefix  = "file:"
+	envTokenPrefix   = "env:"
+	execTokenPrefix  = "exec:"
+	doctlTokenPrefix = "doctl:"
+)
+
+var ErrTokenNotFound = errors.New("DigitalOcean token not found")
+
+type doctlConfig struct {
+	AccessToken  string            `yaml:"access-token"`
+	Context      string            `yaml:"context"`
+	AuthContexts map[string]string `yaml:"auth-contexts"`
+}
+
+// Returns whether the token is a reference that ResolveToken reads from a file, the environment or a command
+func IsTokenReference(token string) bool {
+	for _, prefix := range []string{fileTokenPrefix, envTokenPrefix, execTokenPrefix, doctlTokenPrefix} {
+		if strings.HasPrefix(token, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Resolves a token reference to the token it points to. Supported references are
+// file:<path>, env:<variable>, exec:<command> and doctl:[config path], anything else is returned as is.
+func ResolveToken(token string) (string, error) {
+	var resolved string
+	var err error
+
+	switch {
+	case strings.HasPrefix(token, fileTokenPrefix):
+		resolved, err = readTokenFile(strings.TrimPrefix(token, fileTokenPrefix))
+	case strings.HasPrefix(token, envTokenPrefix):
+		variable := strings.TrimPrefix(token, envTokenPrefix)
+		resolved = os.Getenv(variable)
+		if resolved == "" {
+			err = fmt.Errorf("environment variable %s is empty", variable)
+		}
+	case strings.HasPrefix(token, execTokenPrefix):
+		resolved, err = execTokenHelper(strings.TrimPrefix(token, execTokenPrefix))
+	case strings.HasPrefix(token, doctlTokenPrefix):
+		resolved, err = ReadDoctlToken(strings.TrimPrefix(token, doctlTokenPrefix))
+	default:
+		resolved = token
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	resolved = strings.TrimSpace(resolved)
+	if resolved == "" {
+		return "", ErrTokenNotFound
+	}
+
+	return resolved, nil
+}
+
+// Returns the token of the active context in a doctl config file, the default config file is used if path is empty
+func ReadDoctlToken(path string) (string, error) {
+	if path == "" {
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(configDir, "doctl", "config.yaml")
+	}
+
+	content, err := os.ReadFile(expandHome(path))
+	if err != nil {
+		return "", fmt.Errorf("error reading doctl config: %v", err)
+	}
+
+	config := doctlConfig{}
+	err = yaml.Unmarshal(content, &config)
+	if err != nil {
+		return "", fmt.Errorf("error parsing doctl config %s: %v", path, err)
+	}
+
+	if config.Context == "" || config.Context == "default" {
+		return config.AccessToken, nil
+	}
+
+	token, ok := config.AuthContexts[config.Context]
+	if !ok {
+		return "", fmt.Errorf("doctl context %s not found in %s", config.Context, path)
+	}
+
+	return token, nil
+}
+
+func readTokenFile(path string) (string, error) {
+	content, err := os.ReadFile(expandHome(path))
+	if err != nil {
+		return "", fmt.Errorf("error reading token file: %v", err)
+	}
+
+	return string(content), nil
+}
+
+func execTokenHelper(command string) (string, error) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("error running token helper: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
+
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+
+	return filepath.Join(homeDir, path[2:])
+}
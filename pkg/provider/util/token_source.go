@@ -0,0 +1,129 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+var ErrTokenRevoked = errors.New("DigitalOcean token revoked or expired")
+
+// Token source that resolves the token lazily and again after it was rejected,
+// so tokens rotated in a file, environment variable or credential helper are picked up without a restart
+type RotatingTokenSource struct {
+	resolve func() (string, error)
+
+	mu    sync.Mutex
+	token string
+}
+
+func NewRotatingTokenSource(resolve func() (string, error)) *RotatingTokenSource {
+	return &RotatingTokenSource{resolve: resolve}
+}
+
+func (s *RotatingTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == "" {
+		token, err := s.resolve()
+		if err != nil {
+			return nil, err
+		}
+		s.token = token
+	}
+
+	return &oauth2.Token{AccessToken: s.token}, nil
+}
+
+// Re-resolves the token if it is still the rejected one and returns whether a different token is now in use
+func (s *RotatingTokenSource) refresh(rejected string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Another request already refreshed the token
+	if s.token != rejected {
+		return true, nil
+	}
+
+	token, err := s.resolve()
+	if err != nil {
+		return false, err
+	}
+	s.token = token
+
+	return token != rejected, nil
+}
+
+// Authenticates requests with the token source and retries a request once with a refreshed token when it is rejected
+type TokenRefreshTransport struct {
+	Source *RotatingTokenSource
+	Base   http.RoundTripper
+}
+
+func (t *TokenRefreshTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.Source.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.send(req, token.AccessToken)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// The request can not be retried if its body was already consumed
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	refreshed, err := t.Source.refresh(token.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to refresh token: %v", ErrTokenRevoked, err)
+	} else if !refreshed {
+		return nil, fmt.Errorf("%w: the API rejected the token and resolving it again returned the same token", ErrTokenRevoked)
+	}
+
+	token, err = t.Source.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+
+	resp, err = t.send(req, token.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: the API rejected the refreshed token", ErrTokenRevoked)
+	}
+
+	return resp, nil
+}
+
+func (t *TokenRefreshTransport) send(req *http.Request, token string) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	// Round trippers must not modify the original request
+	authReq := req.Clone(req.Context())
+	authReq.Header.Set("Authorization", "Bearer "+token)
+
+	return base.RoundTrip(authReq)
+}
@@ -0,0 +1,111 @@
+package util
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTokenRefreshTransport(t *testing.T) {
+	tests := []struct {
+		name string
+		// Tokens returned by consecutive resolves, the last one is repeated
+		resolved     []string
+		validToken   string
+		wantStatus   int
+		wantRequests int
+		wantErr      error
+	}{
+		{
+			name:         "valid token",
+			resolved:     []string{"valid"},
+			validToken:   "valid",
+			wantStatus:   http.StatusOK,
+			wantRequests: 1,
+		},
+		{
+			name:         "rotated token",
+			resolved:     []string{"old", "valid"},
+			validToken:   "valid",
+			wantStatus:   http.StatusOK,
+			wantRequests: 2,
+		},
+		{
+			name:         "unchanged token",
+			resolved:     []string{"revoked"},
+			validToken:   "valid",
+			wantRequests: 1,
+			wantErr:      ErrTokenRevoked,
+		},
+		{
+			name:         "rotated token rejected",
+			resolved:     []string{"old", "also-revoked"},
+			validToken:   "valid",
+			wantRequests: 2,
+			wantErr:      ErrTokenRevoked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+
+				body, _ := io.ReadAll(r.Body)
+				if r.Method == http.MethodPost && string(body) != "payload" {
+					t.Errorf("request body = %q, want %q", string(body), "payload")
+				}
+
+				if r.Header.Get("Authorization") != "Bearer "+tt.validToken {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			resolves := 0
+			source := NewRotatingTokenSource(func() (string, error) {
+				token := tt.resolved[min(resolves, len(tt.resolved)-1)]
+				resolves++
+				return token, nil
+			})
+			client := &http.Client{Transport: &TokenRefreshTransport{Source: source}}
+
+			// The body must be sent again when the request is retried
+			resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			} else {
+				resp.Body.Close()
+				if resp.StatusCode != tt.wantStatus {
+					t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+				}
+			}
+
+			if requests != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestTokenRefreshTransportResolveError(t *testing.T) {
+	source := NewRotatingTokenSource(func() (string, error) {
+		return "", ErrTokenNotFound
+	})
+	client := &http.Client{Transport: &TokenRefreshTransport{Source: source}}
+
+	_, err := client.Get("http://127.0.0.1:0")
+	if !errors.Is(err, ErrTokenNotFound) {
+		t.Errorf("error = %v, want %v", err, ErrTokenNotFound)
+	}
+}
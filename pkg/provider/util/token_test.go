@@ -0,0 +1,98 @@
+package util
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveToken(t *testing.T) {
+	dir := t.TempDir()
+
+	tokenFile := filepath.Join(dir, "token")
+	err := os.WriteFile(tokenFile, []byte("file-token\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doctlConfig := filepath.Join(dir, "config.yaml")
+	err = os.WriteFile(doctlConfig, []byte("access-token: default-token\ncontext: work\nauth-contexts:\n  work: work-token\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doctlDefaultConfig := filepath.Join(dir, "default.yaml")
+	err = os.WriteFile(doctlDefaultConfig, []byte("access-token: default-token\ncontext: default\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doctlMissingContextConfig := filepath.Join(dir, "missing.yaml")
+	err = os.WriteFile(doctlMissingContextConfig, []byte("access-token: default-token\ncontext: personal\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("DAYTONA_TEST_TOKEN", " env-token ")
+	t.Setenv("DAYTONA_TEST_EMPTY_TOKEN", "")
+
+	tests := []struct {
+		name    string
+		token   string
+		want    string
+		wantErr bool
+	}{
+		{name: "plain token", token: "dop_v1_abc", want: "dop_v1_abc"},
+		{name: "file", token: "file:" + tokenFile, want: "file-token"},
+		{name: "missing file", token: "file:" + filepath.Join(dir, "missing"), wantErr: true},
+		{name: "env", token: "env:DAYTONA_TEST_TOKEN", want: "env-token"},
+		{name: "empty env", token: "env:DAYTONA_TEST_EMPTY_TOKEN", wantErr: true},
+		{name: "exec", token: "exec:echo exec-token", want: "exec-token"},
+		{name: "failing exec", token: "exec:echo failed >&2; exit 1", wantErr: true},
+		{name: "empty exec output", token: "exec:true", wantErr: true},
+		{name: "doctl context", token: "doctl:" + doctlConfig, want: "work-token"},
+		{name: "doctl default context", token: "doctl:" + doctlDefaultConfig, want: "default-token"},
+		{name: "doctl missing context", token: "doctl:" + doctlMissingContextConfig, wantErr: true},
+		{name: "empty", token: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveToken(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveToken(%q) error = %v, wantErr %v", tt.token, err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ResolveToken(%q) = %q, want %q", tt.token, got, tt.want)
+			}
+		})
+	}
+
+	_, err = ResolveToken("exec:true")
+	if !errors.Is(err, ErrTokenNotFound) {
+		t.Errorf("ResolveToken() with empty output error = %v, want %v", err, ErrTokenNotFound)
+	}
+}
+
+func TestIsTokenReference(t *testing.T) {
+	tests := []struct {
+		token string
+		want  bool
+	}{
+		{"dop_v1_abc", false},
+		{"", false},
+		{"file:/etc/token", true},
+		{"env:DIGITALOCEAN_TOKEN", true},
+		{"exec:pass show digitalocean", true},
+		{"doctl:", true},
+	}
+
+	for _, tt := range tests {
+		got := IsTokenReference(tt.token)
+		if got != tt.want {
+			t.Errorf("IsTokenReference(%q) = %v, want %v", tt.token, got, tt.want)
+		}
+	}
+}
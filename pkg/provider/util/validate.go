@@ -0,0 +1,130 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/daytonaio/daytona-provider-digitalocean/pkg/types"
+	"github.com/digitalocean/godo"
+)
+
+const (
+	minVolumeSizeGigaBytes = 1
+	maxVolumeSizeGigaBytes = 16 * 1024
+)
+
+// How the target's droplet is about to change, which decides the options checked against the DigitalOcean API
+type DropletChange int
+
+const (
+	// Only options that do not depend on the API are checked
+	DropletUnchanged DropletChange = iota
+	// The region and size are checked as well
+	DropletResized
+	// The region, size, image and VPC are checked as well
+	DropletCreated
+)
+
+// Checks the target options and returns an error describing every problem found
+// The sizes are required unless the droplet is unchanged
+func ValidateTargetOptions(client *godo.Client, targetOptions *types.TargetOptions, sizes []godo.Size, change DropletChange) error {
+	errs := []error{}
+
+	if targetOptions.DiskSize < minVolumeSizeGigaBytes || targetOptions.DiskSize > maxVolumeSizeGigaBytes {
+		errs = append(errs, fmt.Errorf("disk size must be between %d and %d GB, got %d", minVolumeSizeGigaBytes, maxVolumeSizeGigaBytes, targetOptions.DiskSize))
+	}
+
+	if targetOptions.IdleTimeout < 0 {
+		errs = append(errs, fmt.Errorf("idle timeout must not be negative, got %d", targetOptions.IdleTimeout))
+	}
+
+	if targetOptions.MaxLifetime != nil && *targetOptions.MaxLifetime != "" {
+		maxLifetime, err := time.ParseDuration(*targetOptions.MaxLifetime)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid max lifetime %s: %v", *targetOptions.MaxLifetime, err))
+		} else if maxLifetime <= 0 {
+			errs = append(errs, fmt.Errorf("max lifetime must be positive, got %s", *targetOptions.MaxLifetime))
+		}
+
+		// Raw tokens are never persisted, so the target could not be reaped after a provider restart
+		if targetOptions.AuthToken != nil && *targetOptions.AuthToken != "" && !IsTokenReference(*targetOptions.AuthToken) {
+			errs = append(errs, errors.New("max lifetime requires the auth token to be empty or a token reference"))
+		}
+	}
+
+	if targetOptions.FirewallInboundRules != nil {
+		_, err := ParseFirewallInboundRules(*targetOptions.FirewallInboundRules)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if targetOptions.Tags != nil {
+		_, err := ParseTags(*targetOptions.Tags)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if change == DropletUnchanged {
+		return errors.Join(errs...)
+	}
+
+	regions, err := listRegions(client)
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+
+	// An unknown region is reported along with the other problems, the options depending on it are checked without it
+	var region *godo.Region
+	regionIndex := slices.IndexFunc(regions, func(r godo.Region) bool { return r.Slug == targetOptions.Region })
+	if regionIndex == -1 {
+		errs = append(errs, fmt.Errorf("region %s does not exist", targetOptions.Region))
+	} else {
+		region = &regions[regionIndex]
+		if !region.Available {
+			errs = append(errs, fmt.Errorf("region %s is not available", region.Slug))
+		}
+	}
+
+	size, err := FindSize(sizes, targetOptions.Size)
+	if err != nil {
+		errs = append(errs, err)
+	} else if region != nil && (!size.Available || !slices.Contains(region.Sizes, size.Slug)) {
+		errs = append(errs, fmt.Errorf("size %s is not available in region %s", targetOptions.Size, region.Slug))
+	}
+
+	if change != DropletCreated {
+		return errors.Join(errs...)
+	}
+
+	regionSlug := ""
+	if region != nil {
+		regionSlug = region.Slug
+	}
+
+	_, err = GetDropletCreateImage(client, targetOptions.Image, regionSlug)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if region != nil && targetOptions.VpcUuid != nil && *targetOptions.VpcUuid != "" {
+		_, err = GetVpc(client, *targetOptions.VpcUuid, region.Slug)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func listRegions(client *godo.Client) ([]godo.Region, error) {
+	regions, err := listAll(client.Regions.List)
+	if err != nil {
+		return nil, fmt.Errorf("error listing regions: %v", err)
+	}
+
+	return regions, nil
+}
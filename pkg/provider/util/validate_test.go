@@ -0,0 +1,64 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/daytonaio/daytona-provider-digitalocean/pkg/types"
+)
+
+func TestValidateTargetOptions(t *testing.T) {
+	ptr := func(s string) *string { return &s }
+
+	tests := []struct {
+		name    string
+		options types.TargetOptions
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			options: types.TargetOptions{DiskSize: 20},
+		},
+		{
+			name:    "disk too small",
+			options: types.TargetOptions{DiskSize: 0},
+			wantErr: true,
+		},
+		{
+			name:    "negative idle timeout",
+			options: types.TargetOptions{DiskSize: 20, IdleTimeout: -1},
+			wantErr: true,
+		},
+		{
+			name:    "max lifetime with default token",
+			options: types.TargetOptions{DiskSize: 20, MaxLifetime: ptr("8h")},
+		},
+		{
+			name:    "max lifetime with token reference",
+			options: types.TargetOptions{DiskSize: 20, MaxLifetime: ptr("8h"), AuthToken: ptr("env:DIGITALOCEAN_TOKEN")},
+		},
+		{
+			name:    "max lifetime with raw token",
+			options: types.TargetOptions{DiskSize: 20, MaxLifetime: ptr("8h"), AuthToken: ptr("dop_v1_abc")},
+			wantErr: true,
+		},
+		{
+			name:    "raw token without max lifetime",
+			options: types.TargetOptions{DiskSize: 20, AuthToken: ptr("dop_v1_abc")},
+		},
+		{
+			name:    "negative max lifetime",
+			options: types.TargetOptions{DiskSize: 20, MaxLifetime: ptr("-1h")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Options that do not depend on the API are checked without a client
+			err := ValidateTargetOptions(nil, &tt.options, nil, DropletUnchanged)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTargetOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
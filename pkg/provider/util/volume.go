@@ -0,0 +1,26 @@
+package util
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/digitalocean/godo"
+)
+
+func GetVolumeDevicePath(name string) string {
+	return fmt.Sprintf("/dev/disk/by-id/scsi-0DO_Volume_%s", name)
+}
+
+// Grows the volume to the given size, volumes can not be shrunk
+func ResizeVolume(client *godo.Client, volume *godo.Volume, sizeGigaBytes int) error {
+	if int64(sizeGigaBytes) < volume.SizeGigaBytes {
+		return fmt.Errorf("disk size can not be decreased from %d GB to %d GB", volume.SizeGigaBytes, sizeGigaBytes)
+	}
+
+	action, _, err := client.StorageActions.Resize(context.Background(), volume.ID, sizeGigaBytes, volume.Region.Slug)
+	if err != nil {
+		return fmt.Errorf("error resizing volume: %v", err)
+	}
+
+	return WaitForAction(client, action)
+}
@@ -0,0 +1,65 @@
+package util
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/digitalocean/godo"
+)
+
+// Replaces the volume with a snapshot of the same name and tags
+// Does nothing if the volume does not exist, e.g. because it was already snapshotted
+func SnapshotVolume(client *godo.Client, name string) error {
+	volume, err := GetVolumeByName(client, name)
+	if err != nil {
+		return err
+	} else if volume == nil {
+		return nil
+	}
+
+	// A previous attempt might have failed before deleting the volume
+	err = DeleteVolumeSnapshot(client, name)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = client.Storage.CreateSnapshot(context.Background(), &godo.SnapshotCreateRequest{
+		VolumeID: volume.ID,
+		Name:     name,
+		Tags:     volume.Tags,
+	})
+	if err != nil {
+		return fmt.Errorf("error creating volume snapshot: %v", err)
+	}
+
+	return DeleteVolume(client, name)
+}
+
+func GetVolumeSnapshotByName(client *godo.Client, name string) (*godo.Snapshot, error) {
+	snapshots, err := listAll(client.Snapshots.ListVolume)
+	if err != nil {
+		return nil, fmt.Errorf("error listing volume snapshots: %v", err)
+	}
+
+	for _, snapshot := range snapshots {
+		if snapshot.Name == name {
+			return &snapshot, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func DeleteVolumeSnapshot(client *godo.Client, name string) error {
+	snapshot, err := GetVolumeSnapshotByName(client, name)
+	if err != nil {
+		return err
+	}
+
+	if snapshot == nil {
+		return nil
+	}
+
+	_, err = client.Snapshots.Delete(context.Background(), snapshot.ID)
+	return err
+}
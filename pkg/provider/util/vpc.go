@@ -0,0 +1,51 @@
+package util
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/digitalocean/godo"
+)
+
+func GetVpcName(region string) string {
+	return fmt.Sprintf("daytona-%s", region)
+}
+
+func GetVpc(client *godo.Client, vpcUuid string, region string) (*godo.VPC, error) {
+	vpc, _, err := client.VPCs.Get(context.Background(), vpcUuid)
+	if err != nil {
+		return nil, fmt.Errorf("error getting vpc: %v", err)
+	}
+
+	if vpc.RegionSlug != region {
+		return nil, fmt.Errorf("vpc %s is in region %s, expected %s", vpcUuid, vpc.RegionSlug, region)
+	}
+
+	return vpc, nil
+}
+
+// Returns the dedicated Daytona VPC for the region and creates it if it does not exist
+func GetOrCreateDaytonaVpc(client *godo.Client, region string) (*godo.VPC, error) {
+	name := GetVpcName(region)
+	vpcs, err := listAll(client.VPCs.List)
+	if err != nil {
+		return nil, fmt.Errorf("error listing vpcs: %v", err)
+	}
+
+	for _, vpc := range vpcs {
+		if vpc.Name == name && vpc.RegionSlug == region {
+			return vpc, nil
+		}
+	}
+
+	vpc, _, err := client.VPCs.Create(context.Background(), &godo.VPCCreateRequest{
+		Name:        name,
+		RegionSlug:  region,
+		Description: "Dedicated VPC for Daytona targets",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating vpc: %v", err)
+	}
+
+	return vpc, nil
+}
@@ -2,12 +2,16 @@ package util
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/daytonaio/daytona/pkg/models"
 	"github.com/digitalocean/godo"
 )
 
+var ErrDropletNotFound = errors.New("no droplet found")
+
 func GetDropletName(target *models.Target) string {
 	return fmt.Sprintf("daytona-%s", target.Id)
 }
@@ -22,7 +26,7 @@ func GetDroplet(client *godo.Client, dropletName string) (*godo.Droplet, error)
 		return &droplets[0], nil
 	}
 
-	return nil, fmt.Errorf("no droplet found with name %s", dropletName)
+	return nil, fmt.Errorf("%w with name %s", ErrDropletNotFound, dropletName)
 }
 
 func GetVolumeByName(client *godo.Client, name string) (*godo.Volume, error) {
@@ -37,3 +41,23 @@ func GetVolumeByName(client *godo.Client, name string) (*godo.Volume, error) {
 
 	return &volumes[0], nil
 }
+
+func WaitForDropletStatus(client *godo.Client, dropletId int, status string, timeout time.Duration) (*godo.Droplet, error) {
+	startTime := time.Now()
+	for {
+		if time.Since(startTime) > timeout {
+			return nil, fmt.Errorf("timeout: droplet %d did not reach status %s after %f minutes", dropletId, status, timeout.Minutes())
+		}
+
+		droplet, _, err := client.Droplets.Get(context.Background(), dropletId)
+		if err != nil {
+			return nil, fmt.Errorf("error getting droplet: %v", err)
+		}
+
+		if droplet.Status == status {
+			return droplet, nil
+		}
+
+		time.Sleep(5 * time.Second)
+	}
+}
@@ -1,4 +1,34 @@
 package types
 
+import "fmt"
+
 type WorkspaceMetadata struct {
 }
+
+type TargetMetadata struct {
+	EstimatedCost *CostEstimate `json:"estimatedCost,omitempty"`
+}
+
+// Estimated cost of a target in USD
+type CostEstimate struct {
+	Size           string  `json:"size"`
+	DropletHourly  float64 `json:"dropletHourly"`
+	DropletMonthly float64 `json:"dropletMonthly"`
+	DiskSize       int     `json:"diskSize"`
+	VolumeHourly   float64 `json:"volumeHourly"`
+	VolumeMonthly  float64 `json:"volumeMonthly"`
+	BackupsMonthly float64 `json:"backupsMonthly,omitempty"`
+	TotalHourly    float64 `json:"totalHourly"`
+	TotalMonthly   float64 `json:"totalMonthly"`
+}
+
+func (c *CostEstimate) String() string {
+	estimate := fmt.Sprintf("Estimated cost: $%.2f/month ($%.4f/hour) - droplet %s $%.2f/month, %d GB volume $%.2f/month",
+		c.TotalMonthly, c.TotalHourly, c.Size, c.DropletMonthly, c.DiskSize, c.VolumeMonthly)
+
+	if c.BackupsMonthly > 0 {
+		estimate += fmt.Sprintf(", backups $%.2f/month", c.BackupsMonthly)
+	}
+
+	return estimate
+}
@@ -2,16 +2,42 @@ package types
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/daytonaio/daytona/pkg/models"
 )
 
+type StopStrategy string
+
+const (
+	StopStrategyDeleteDroplet  StopStrategy = "delete-droplet"
+	StopStrategyPowerOff       StopStrategy = "power-off"
+	StopStrategySnapshotVolume StopStrategy = "snapshot-volume"
+)
+
 type TargetOptions struct {
-	Region    string  `json:"Region"`               // Region slug
-	Size      string  `json:"Size"`                 // Size slug
-	DiskSize  int     `json:"Disk Size"`            // Disk Size integer
-	Image     string  `json:"Image"`                // Image slug
-	AuthToken *string `json:"Auth Token,omitempty"` // Auth token
+	Region               string       `json:"Region"`                           // Region slug
+	Size                 string       `json:"Size"`                             // Size slug
+	DiskSize             int          `json:"Disk Size"`                        // Disk Size integer
+	Image                string       `json:"Image"`                            // Image slug, ID or snapshot name
+	AuthToken            *string      `json:"Auth Token,omitempty"`             // Auth token
+	SshKeys              *string      `json:"SSH Keys,omitempty"`               // Comma separated SSH key fingerprints, IDs or public keys
+	FirewallInboundRules *string      `json:"Firewall Inbound Rules,omitempty"` // Comma separated inbound firewall rules
+	ExposeDockerApi      bool         `json:"Expose Docker API,omitempty"`      // Bind the Docker API to all interfaces
+	VpcUuid              *string      `json:"VPC UUID,omitempty"`               // VPC UUID
+	CreateVpc            bool         `json:"Create VPC,omitempty"`             // Create a dedicated Daytona VPC if no VPC UUID is set
+	PrivateNetworking    bool         `json:"Private Networking,omitempty"`     // Enable private networking
+	BakeImage            bool         `json:"Bake Image,omitempty"`             // Create a snapshot with Docker and the agent preinstalled
+	StopStrategy         StopStrategy `json:"Stop Strategy,omitempty"`          // What happens to the droplet and volume when the target is stopped
+	Project              *string      `json:"Project,omitempty"`                // Project name or UUID
+	Tags                 *string      `json:"Tags,omitempty"`                   // Comma separated custom tags
+	Monitoring           bool         `json:"Monitoring,omitempty"`             // Install the DigitalOcean metrics agent
+	Backups              bool         `json:"Backups,omitempty"`                // Enable weekly droplet backups
+	IPv6                 bool         `json:"IPv6,omitempty"`                   // Enable IPv6
+	ReservedIp           *string      `json:"Reserved IP,omitempty"`            // "new" or an existing reserved IP address
+	MaxMonthlyCost       *float64     `json:"Max Monthly Cost,omitempty"`       // Maximum estimated monthly cost in USD
+	IdleTimeout          int          `json:"Idle Timeout,omitempty"`           // Minutes of inactivity after which the droplet is powered off
+	MaxLifetime          *string      `json:"Max Lifetime,omitempty"`           // Duration after which the target is destroyed, e.g. 8h
 }
 
 func GetTargetConfigManifest() *models.TargetConfigManifest {
@@ -31,11 +57,107 @@ func GetTargetConfigManifest() *models.TargetConfigManifest {
 		"Image": models.TargetConfigProperty{
 			Type:         models.TargetConfigPropertyTypeString,
 			DefaultValue: "docker-20-04",
+			Description:  "Image slug, numeric image ID or the name of a snapshot or custom image.",
 		},
 		"Auth Token": models.TargetConfigProperty{
 			Type:        models.TargetConfigPropertyTypeString,
 			InputMasked: true,
-			Description: "If empty, token will be fetched from the DIGITALOCEAN_ACCESS_TOKEN environment variable.",
+			Description: "A token or, if allowTokenReferences is set in the provider's config.json, a reference to one: file:<path>, env:<variable>, exec:<command> printing the token or doctl:[config path] for the active doctl context.\n" +
+				"If empty, token will be fetched from the DIGITALOCEAN_ACCESS_TOKEN environment variable, which may also be a reference, or, if useDoctlConfig is set, the active doctl context.",
+		},
+		"SSH Keys": models.TargetConfigProperty{
+			Type: models.TargetConfigPropertyTypeString,
+			Description: "Comma separated list of DigitalOcean SSH key fingerprints or IDs that will be added to the droplet.\n" +
+				"Public keys (e.g. ssh-ed25519 AAAA...) are also accepted and will be uploaded to your account if missing.\n" +
+				"The target's firewall denies public SSH unless a rule like tcp:22:<cidr> is added to Firewall Inbound Rules.",
+		},
+		"Firewall Inbound Rules": models.TargetConfigProperty{
+			Type: models.TargetConfigPropertyTypeString,
+			Description: "Comma separated list of public inbound traffic allowed by the target's firewall in the form of " +
+				"<protocol>:<ports>[:<source>;<source>...], e.g. tcp:22:203.0.113.0/24,udp:41641.\n" +
+				"If empty, all public inbound traffic is denied and the target is only reachable through the Daytona network.",
+		},
+		"Expose Docker API": models.TargetConfigProperty{
+			Type:         models.TargetConfigPropertyTypeBoolean,
+			DefaultValue: "false",
+			Description: "If true, the unauthenticated Docker API is bound to port 2375 on all interfaces of the droplet.\n" +
+				"If false, Docker is only reachable through its unix socket which the provider accesses through the Daytona agent.",
+		},
+		"VPC UUID": models.TargetConfigProperty{
+			Type:        models.TargetConfigPropertyTypeString,
+			Description: "UUID of an existing VPC in the target's region the droplet will be placed in.\nIf empty, the region's default VPC is used unless Create VPC is enabled.",
+		},
+		"Create VPC": models.TargetConfigProperty{
+			Type:         models.TargetConfigPropertyTypeBoolean,
+			DefaultValue: "false",
+			Description:  "If true and VPC UUID is empty, the droplet is placed in a dedicated Daytona VPC which is created per region if it does not exist.",
+		},
+		"Private Networking": models.TargetConfigProperty{
+			Type:         models.TargetConfigPropertyTypeBoolean,
+			DefaultValue: "false",
+			Description:  "Enable private networking on the droplet.",
+		},
+		"Bake Image": models.TargetConfigProperty{
+			Type:         models.TargetConfigPropertyTypeBoolean,
+			DefaultValue: "false",
+			Description: "If true, a snapshot of the image with Docker and the Daytona agent preinstalled is created on first use.\n" +
+				"Targets using the same image and Daytona version boot from the snapshot if it exists, regardless of this option.\n" +
+				"Snapshots of the same image baked for other Daytona versions are deleted once the new one is created.",
+		},
+		"Stop Strategy": models.TargetConfigProperty{
+			Type:         models.TargetConfigPropertyTypeOption,
+			DefaultValue: string(StopStrategyDeleteDroplet),
+			Options:      []string{string(StopStrategyDeleteDroplet), string(StopStrategyPowerOff), string(StopStrategySnapshotVolume)},
+			Description: "delete-droplet: deletes the droplet and keeps the volume.\n" +
+				"power-off: keeps the droplet powered off for a fast resume. Powered off droplets are still billed.\n" +
+				"snapshot-volume: snapshots the volume and deletes the droplet and volume for the cheapest storage while stopped.",
+		},
+		"Project": models.TargetConfigProperty{
+			Type:        models.TargetConfigPropertyTypeString,
+			Description: "Name or UUID of the DigitalOcean project the droplet and volume are assigned to.\nIf empty, the account's default project is used.",
+		},
+		"Tags": models.TargetConfigProperty{
+			Type: models.TargetConfigPropertyTypeString,
+			Description: "Comma separated list of tags applied to all resources created for the target.\n" +
+				"Tags identifying the target and the Daytona server are always applied.",
+		},
+		"Monitoring": models.TargetConfigProperty{
+			Type:         models.TargetConfigPropertyTypeBoolean,
+			DefaultValue: "false",
+			Description:  "Install the DigitalOcean metrics agent on the droplet.",
+		},
+		"Backups": models.TargetConfigProperty{
+			Type:         models.TargetConfigPropertyTypeBoolean,
+			DefaultValue: "false",
+			Description:  "Enable weekly backups of the droplet. Backups do not include the volume holding the workspaces.",
+		},
+		"IPv6": models.TargetConfigProperty{
+			Type:         models.TargetConfigPropertyTypeBoolean,
+			DefaultValue: "false",
+			Description:  "Enable IPv6 on the droplet.",
+		},
+		"Reserved IP": models.TargetConfigProperty{
+			Type: models.TargetConfigPropertyTypeString,
+			Description: "Set to \"new\" to allocate a reserved IP for the target which is kept while the target is stopped and released when it is destroyed.\n" +
+				"Set to an existing reserved IP address to assign it to the target's droplet. Existing reserved IPs are not released.\n" +
+				"Outbound traffic of the droplet is routed through the reserved IP, so it can be allowlisted by third parties.",
+			Suggestions: []string{"new"},
+		},
+		"Max Monthly Cost": models.TargetConfigProperty{
+			Type: models.TargetConfigPropertyTypeFloat,
+			Description: "Maximum estimated monthly cost of the target in USD. Creating, resizing or growing the target fails if the estimate exceeds it.\n" +
+				"If empty, the provider-wide default from config.json in the provider's base path is used.",
+		},
+		"Idle Timeout": models.TargetConfigProperty{
+			Type:         models.TargetConfigPropertyTypeInt,
+			DefaultValue: "0",
+			Description: "Minutes without SSH or IDE sessions, commands executed in workspaces or CPU load after which the workspaces are stopped and the droplet is powered off.\n" +
+				"Powered off droplets are still billed and are resumed when the target is started, which also starts the stopped workspaces again. Set to 0 to disable.",
+		},
+		"Max Lifetime": models.TargetConfigProperty{
+			Type: models.TargetConfigPropertyTypeString,
+			Description: "Duration after which the target's droplet, volume and volume snapshot are destroyed, e.g. 90m or 72h.\n" +
+				"The expiry is set when the target is created. Requires the Auth Token to be empty or a token reference. Leave empty to keep the target until it is removed.",
 		},
 	}
 }
@@ -47,5 +169,13 @@ func ParseTargetOptions(optionsJson string) (*TargetOptions, error) {
 		return nil, err
 	}
 
+	switch targetOptions.StopStrategy {
+	case "":
+		targetOptions.StopStrategy = StopStrategyDeleteDroplet
+	case StopStrategyDeleteDroplet, StopStrategyPowerOff, StopStrategySnapshotVolume:
+	default:
+		return nil, fmt.Errorf("invalid stop strategy %s", targetOptions.StopStrategy)
+	}
+
 	return &targetOptions, nil
 }
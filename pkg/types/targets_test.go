@@ -0,0 +1,51 @@
+package types
+
+import "testing"
+
+func TestParseTargetOptionsStopStrategy(t *testing.T) {
+	tests := []struct {
+		name    string
+		options string
+		want    StopStrategy
+		wantErr bool
+	}{
+		{
+			name:    "default",
+			options: `{"Region": "fra1"}`,
+			want:    StopStrategyDeleteDroplet,
+		},
+		{
+			name:    "delete droplet",
+			options: `{"Stop Strategy": "delete-droplet"}`,
+			want:    StopStrategyDeleteDroplet,
+		},
+		{
+			name:    "power off",
+			options: `{"Stop Strategy": "power-off"}`,
+			want:    StopStrategyPowerOff,
+		},
+		{
+			name:    "snapshot volume",
+			options: `{"Stop Strategy": "snapshot-volume"}`,
+			want:    StopStrategySnapshotVolume,
+		},
+		{
+			name:    "invalid",
+			options: `{"Stop Strategy": "hibernate"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetOptions, err := ParseTargetOptions(tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTargetOptions(%s) error = %v, wantErr %v", tt.options, err, tt.wantErr)
+			}
+
+			if !tt.wantErr && targetOptions.StopStrategy != tt.want {
+				t.Errorf("ParseTargetOptions(%s) stop strategy = %s, want %s", tt.options, targetOptions.StopStrategy, tt.want)
+			}
+		})
+	}
+}